@@ -3,18 +3,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
-	"github.com/j0r15/terraform-provider-uptimekuma/internal/provider"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 func main() {
 	fmt.Println("Testing notification retrieval...")
 
+	ctx := context.Background()
+
 	// Create client
-	client, err := provider.NewClient("http://localhost:3001", "admin", "cF96H*L9LA3*HiWhx")
+	client, err := uptimekuma.NewClient(ctx, "http://localhost:3001", "admin", "cF96H*L9LA3*HiWhx")
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -28,7 +31,7 @@ func main() {
 
 	// Test getting all notifications
 	fmt.Println("📋 Getting all notifications...")
-	notifications, err := client.GetNotifications()
+	notifications, err := client.GetNotifications(ctx)
 	if err != nil {
 		log.Printf("❌ Failed to get notifications: %v", err)
 	} else {
@@ -40,7 +43,7 @@ func main() {
 
 	// Test getting specific notification by ID 1
 	fmt.Println("🔍 Getting notification ID 1...")
-	notif, err := client.GetNotification(1)
+	notif, err := client.GetNotification(ctx, 1)
 	if err != nil {
 		log.Printf("❌ Failed to get notification ID 1: %v", err)
 	} else {
@@ -0,0 +1,1555 @@
+// Package fakekuma provides an in-memory Uptime Kuma server for tests.
+//
+// The server speaks Engine.IO v4 / Socket.IO over a WebSocket transport and
+// implements the subset of Uptime Kuma socket events used by the provider, so
+// the client and resources can be exercised with httptest and no network.
+package fakekuma
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/totp"
+)
+
+const (
+	// DefaultUsername is the username accepted by a server created without WithCredentials
+	DefaultUsername = "admin"
+	// DefaultPassword is the password accepted by a server created without WithCredentials
+	DefaultPassword = "admin123"
+	// DefaultToken is the session token returned on a successful login
+	DefaultToken = "fake-jwt-token"
+	// DefaultVersion is the Uptime Kuma version reported by a server created without WithVersion
+	DefaultVersion = "1.23.16"
+)
+
+// handler processes a single socket event. A nil result means the event is
+// not acknowledged.
+type handler func(c *conn, args []json.RawMessage) interface{}
+
+// ackThen is returned by handlers that push events after acknowledging, the
+// way Uptime Kuma does for login and deleteMonitor
+type ackThen struct {
+	result interface{}
+	then   func()
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithCredentials sets the username and password accepted by the login event
+func WithCredentials(username, password string) Option {
+	return func(s *Server) {
+		s.Username = username
+		s.Password = password
+	}
+}
+
+// WithPingInterval sets the Engine.IO ping interval and timeout announced in
+// the open packet and enforced by the server
+func WithPingInterval(interval, timeout time.Duration) Option {
+	return func(s *Server) {
+		s.pingInterval = interval
+		s.pingTimeout = timeout
+	}
+}
+
+// WithPathPrefix serves the instance under a sub-path, as behind a reverse
+// proxy. URL includes the prefix.
+func WithPathPrefix(prefix string) Option {
+	return func(s *Server) {
+		if trimmed := strings.Trim(prefix, "/"); trimmed != "" {
+			s.pathPrefix = "/" + trimmed
+		}
+	}
+}
+
+// WithTLS serves the instance over HTTPS with a self-signed certificate for
+// example.com and 127.0.0.1; see CACertPEM
+func WithTLS() Option {
+	return func(s *Server) {
+		s.useTLS = true
+	}
+}
+
+// WithClientCAs serves over HTTPS like WithTLS and requires clients to
+// present a certificate signed by one of pool
+func WithClientCAs(pool *x509.CertPool) Option {
+	return func(s *Server) {
+		s.useTLS = true
+		s.clientCAs = pool
+	}
+}
+
+// WithRequiredHeader rejects requests that do not carry the header, like an
+// access proxy in front of the instance
+func WithRequiredHeader(name, value string) Option {
+	return func(s *Server) {
+		s.requiredHeaders.Set(name, value)
+	}
+}
+
+// WithTOTP enables two-factor authentication for the account, accepting codes
+// generated from the base32 secret
+func WithTOTP(secret string) Option {
+	return func(s *Server) {
+		s.totpSecret = secret
+	}
+}
+
+// WithVersion sets the Uptime Kuma version reported by the info event
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithListDelay delays the monitor and notification lists sent after login,
+// and the monitor list updates sent after changes, like a large instance that
+// takes a while to load them. Acknowledgements are not delayed.
+func WithListDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.listDelay = delay
+	}
+}
+
+// Server is an in-memory Uptime Kuma instance
+type Server struct {
+	// URL is the base URL of the server, suitable for NewClient
+	URL string
+
+	Username string
+	Password string
+
+	httpServer      *httptest.Server
+	upgrader        websocket.Upgrader
+	handlers        map[string]handler
+	pathPrefix      string
+	useTLS          bool
+	clientCAs       *x509.CertPool
+	requiredHeaders http.Header
+	totpSecret      string
+	version         string
+	listDelay       time.Duration
+	pingInterval    time.Duration
+	pingTimeout     time.Duration
+
+	mu                 sync.Mutex
+	conns              map[*conn]struct{}
+	nextSID            int
+	monitors           map[int]map[string]interface{}
+	nextMonitorID      int
+	notifications      map[int]map[string]interface{}
+	nextNotificationID int
+	tags               map[int]map[string]interface{}
+	nextTagID          int
+	nextMonitorTagID   int
+	statusPages        map[string]*statusPage
+	nextStatusPageID   int
+	nextGroupID        int
+	events             []string
+	dropNext           map[string]int
+	rateLimitNext      map[string]int
+	ignoreNext         map[string]int
+	pingsPaused        bool
+}
+
+// NewServer starts a fake Uptime Kuma server that is shut down when the test
+// finishes
+func NewServer(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	s := &Server{
+		Username:           DefaultUsername,
+		Password:           DefaultPassword,
+		version:            DefaultVersion,
+		pingInterval:       25 * time.Second,
+		pingTimeout:        20 * time.Second,
+		conns:              make(map[*conn]struct{}),
+		monitors:           make(map[int]map[string]interface{}),
+		nextMonitorID:      1,
+		notifications:      make(map[int]map[string]interface{}),
+		nextNotificationID: 1,
+		tags:               make(map[int]map[string]interface{}),
+		nextTagID:          1,
+		nextMonitorTagID:   1,
+		statusPages:        make(map[string]*statusPage),
+		nextStatusPageID:   1,
+		nextGroupID:        1,
+		dropNext:           make(map[string]int),
+		rateLimitNext:      make(map[string]int),
+		ignoreNext:         make(map[string]int),
+		requiredHeaders:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.handlers = map[string]handler{
+		"login":              s.handleLogin,
+		"loginByToken":       s.handleLoginByToken,
+		"add":                s.handleAdd,
+		"editMonitor":        s.handleEditMonitor,
+		"deleteMonitor":      s.handleDeleteMonitor,
+		"getMonitorList":     s.handleGetMonitorList,
+		"addNotification":    s.handleAddNotification,
+		"deleteNotification": s.handleDeleteNotification,
+		"getTags":            s.handleGetTags,
+		"addTag":             s.handleAddTag,
+		"editTag":            s.handleEditTag,
+		"deleteTag":          s.handleDeleteTag,
+		"addMonitorTag":      s.handleAddMonitorTag,
+		"editMonitorTag":     s.handleEditMonitorTag,
+		"deleteMonitorTag":   s.handleDeleteMonitorTag,
+		"addStatusPage":      s.handleAddStatusPage,
+		"getStatusPage":      s.handleGetStatusPage,
+		"saveStatusPage":     s.handleSaveStatusPage,
+		"deleteStatusPage":   s.handleDeleteStatusPage,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.pathPrefix+"/socket.io/", s.serveSocketIO)
+	mux.HandleFunc(s.pathPrefix+"/api/status-page/", s.serveStatusPage)
+
+	s.httpServer = httptest.NewUnstartedServer(s.checkHeaders(mux))
+	if s.useTLS {
+		if s.clientCAs != nil {
+			s.httpServer.TLS = &tls.Config{
+				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientCAs:  s.clientCAs,
+			}
+		}
+		s.httpServer.StartTLS()
+	} else {
+		s.httpServer.Start()
+	}
+	s.URL = s.httpServer.URL + s.pathPrefix
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// checkHeaders answers 403 to requests missing a required header
+func (s *Server) checkHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name := range s.requiredHeaders {
+			if r.Header.Get(name) != s.requiredHeaders.Get(name) {
+				http.Error(w, "missing "+name, http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CACertPEM returns the PEM-encoded certificate of a server started with
+// WithTLS, for clients to trust
+func (s *Server) CACertPEM() string {
+	cert := s.httpServer.Certificate()
+	if cert == nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+// Close disconnects all clients and shuts the server down
+func (s *Server) Close() {
+	s.DropConnections()
+	s.httpServer.Close()
+}
+
+// DropConnections closes every open client connection without a Socket.IO
+// disconnect packet, simulating a network failure
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.close()
+	}
+}
+
+// DropNext makes the server drop the connection the next time the event is
+// received, before handling or acknowledging it
+func (s *Server) DropNext(event string) {
+	s.mu.Lock()
+	s.dropNext[event]++
+	s.mu.Unlock()
+}
+
+// RateLimitNext makes the server refuse the next n occurrences of the event
+// with the message Uptime Kuma's rate limiter sends
+func (s *Server) RateLimitNext(event string, n int) {
+	s.mu.Lock()
+	s.rateLimitNext[event] += n
+	s.mu.Unlock()
+}
+
+// IgnoreNext makes the server silently discard the next n occurrences of the
+// event, as if their acknowledgements were lost under load
+func (s *Server) IgnoreNext(event string, n int) {
+	s.mu.Lock()
+	s.ignoreNext[event] += n
+	s.mu.Unlock()
+}
+
+// PausePings stops the server from sending Engine.IO pings, so connections
+// look dead to clients while staying open
+func (s *Server) PausePings() {
+	s.mu.Lock()
+	s.pingsPaused = true
+	s.mu.Unlock()
+}
+
+// Events returns the names of all events received from clients, in order
+func (s *Server) Events() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.events...)
+}
+
+// Monitor returns a copy of the stored monitor with the given ID
+func (s *Server) Monitor(id int) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	monitor, ok := s.monitors[id]
+	if !ok {
+		return nil, false
+	}
+	return copyMap(monitor), true
+}
+
+// MonitorIDs returns the IDs of all stored monitors in ascending order
+func (s *Server) MonitorIDs() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedKeys(s.monitors)
+}
+
+// AddMonitor stores a monitor as if it had been created through the UI and
+// returns its ID
+func (s *Server) AddMonitor(monitor map[string]interface{}) int {
+	s.mu.Lock()
+	id := s.storeMonitor(copyMap(monitor))
+	s.mu.Unlock()
+
+	s.broadcastMonitorUpdate(id)
+
+	return id
+}
+
+// RemoveMonitor deletes a monitor as if it had been deleted through the UI
+func (s *Server) RemoveMonitor(id int) {
+	s.mu.Lock()
+	delete(s.monitors, id)
+	s.mu.Unlock()
+
+	s.broadcastMonitorDelete(id)
+}
+
+// Notification returns a copy of the stored notification with the given ID
+func (s *Server) Notification(id int) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notification, ok := s.notifications[id]
+	if !ok {
+		return nil, false
+	}
+	return copyMap(notification), true
+}
+
+// NotificationIDs returns the IDs of all stored notifications in ascending order
+func (s *Server) NotificationIDs() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedKeys(s.notifications)
+}
+
+// Tag returns a copy of the stored tag with the given ID
+func (s *Server) Tag(id int) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag, ok := s.tags[id]
+	if !ok {
+		return nil, false
+	}
+	return copyMap(tag), true
+}
+
+// AddTag stores a tag as if it had been created through the UI and returns
+// its ID
+func (s *Server) AddTag(name, color string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storeTag(name, color)
+}
+
+// RemoveTag deletes a tag as if it had been deleted through the UI
+func (s *Server) RemoveTag(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tags, id)
+}
+
+// StatusPage returns a copy of the configuration of the status page with the
+// given slug, as sent by getStatusPage
+func (s *Server) StatusPage(slug string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, ok := s.statusPages[slug]
+	if !ok {
+		return nil, false
+	}
+	return statusPageJSON(page.config), true
+}
+
+// PublicGroups returns copies of the public groups of a status page in
+// display order
+func (s *Server) PublicGroups(slug string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, ok := s.statusPages[slug]
+	if !ok {
+		return nil
+	}
+	groups := make([]map[string]interface{}, len(page.groups))
+	for i, group := range page.groups {
+		groups[i] = copyMap(group)
+	}
+	return groups
+}
+
+// AddStatusPage creates a status page as if it had been created through the
+// UI
+func (s *Server) AddStatusPage(slug, title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.storeStatusPage(slug, title)
+}
+
+// serveStatusPage answers the public status page API with the configuration
+// and public groups of a page
+func (s *Server) serveStatusPage(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimPrefix(r.URL.Path, s.pathPrefix+"/api/status-page/")
+
+	s.mu.Lock()
+	page, ok := s.statusPages[slug]
+	var body map[string]interface{}
+	if ok {
+		groups := make([]interface{}, len(page.groups))
+		for i, group := range page.groups {
+			groups[i] = s.publicGroupJSON(group)
+		}
+		body = map[string]interface{}{
+			"config":          statusPageJSON(page.config),
+			"incident":        nil,
+			"publicGroupList": groups,
+			"maintenanceList": []interface{}{},
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse("Status Page Not Found"))
+		return
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+// serveSocketIO upgrades the request and runs the Engine.IO session
+func (s *Server) serveSocketIO(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("EIO") != "4" || r.URL.Query().Get("transport") != "websocket" {
+		http.Error(w, "unsupported transport", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.nextSID++
+	c := &conn{
+		server: s,
+		ws:     ws,
+		sid:    fmt.Sprintf("sid-%d", s.nextSID),
+		pong:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	open, _ := json.Marshal(map[string]interface{}{
+		"sid":          c.sid,
+		"upgrades":     []string{},
+		"pingInterval": s.pingInterval.Milliseconds(),
+		"pingTimeout":  s.pingTimeout.Milliseconds(),
+		"maxPayload":   1000000,
+	})
+	if err := c.write("0" + string(open)); err != nil {
+		return
+	}
+
+	go c.heartbeat(s.pingInterval, s.pingTimeout)
+
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.handlePacket(string(message))
+	}
+}
+
+// handleEvent dispatches a Socket.IO event to its handler and sends the
+// acknowledgement if one was requested
+func (s *Server) handleEvent(c *conn, ackID string, event string, args []json.RawMessage) {
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	drop := takeNext(s.dropNext, event)
+	rateLimited := takeNext(s.rateLimitNext, event)
+	ignored := takeNext(s.ignoreNext, event)
+	s.mu.Unlock()
+
+	if drop {
+		c.close()
+		return
+	}
+
+	h, ok := s.handlers[event]
+	if !ok || ignored {
+		return
+	}
+
+	var result interface{}
+	if rateLimited {
+		result = errorResponse("Too frequently, try again later.")
+	} else {
+		result = h(c, args)
+	}
+	var then func()
+	if deferred, ok := result.(ackThen); ok {
+		result, then = deferred.result, deferred.then
+	}
+
+	if result != nil && ackID != "" {
+		if payload, err := json.Marshal([]interface{}{result}); err == nil {
+			c.write("43" + ackID + string(payload))
+		}
+	}
+
+	if then != nil {
+		then()
+	}
+}
+
+// takeNext consumes one pending occurrence of the event from counts
+func takeNext(counts map[string]int, event string) bool {
+	if counts[event] == 0 {
+		return false
+	}
+	counts[event]--
+	return true
+}
+
+func (s *Server) handleLogin(c *conn, args []json.RawMessage) interface{} {
+	var data struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Token    string `json:"token"`
+	}
+	if len(args) < 1 || json.Unmarshal(args[0], &data) != nil {
+		return errorResponse("Invalid login payload.")
+	}
+
+	if data.Username != s.Username || data.Password != s.Password {
+		return errorResponse("Incorrect username or password.")
+	}
+
+	if s.totpSecret != "" {
+		if data.Token == "" {
+			return map[string]interface{}{"tokenRequired": true}
+		}
+		if !s.validTOTP(data.Token) {
+			return errorResponse("authInvalidToken")
+		}
+	}
+
+	c.setLoggedIn()
+
+	// Uptime Kuma acknowledges the login before sending the initial lists
+	return ackThen{
+		result: map[string]interface{}{"ok": true, "token": DefaultToken},
+		then:   func() { s.sendInitialLists(c) },
+	}
+}
+
+func (s *Server) handleLoginByToken(c *conn, args []json.RawMessage) interface{} {
+	var token string
+	if len(args) < 1 || json.Unmarshal(args[0], &token) != nil || token != DefaultToken {
+		return errorResponse("authInvalidToken")
+	}
+
+	c.setLoggedIn()
+
+	return ackThen{
+		result: map[string]interface{}{"ok": true},
+		then:   func() { s.sendInitialLists(c) },
+	}
+}
+
+// validTOTP accepts the code of the current time step or the ones next to it,
+// allowing for clock drift like Uptime Kuma does
+func (s *Server) validTOTP(code string) bool {
+	now := time.Now()
+	for _, step := range []time.Duration{-totp.Period, 0, totp.Period} {
+		if expected, err := totp.Code(s.totpSecret, now.Add(step)); err == nil && expected == code {
+			return true
+		}
+	}
+	return false
+}
+
+// sendInitialLists pushes the server info and lists a client receives after
+// logging in
+func (s *Server) sendInitialLists(c *conn) {
+	c.emit("info", map[string]interface{}{
+		"version":              s.version,
+		"latestVersion":        s.version,
+		"primaryBaseURL":       nil,
+		"serverTimezone":       "UTC",
+		"serverTimezoneOffset": "+00:00",
+	})
+	sendLists := func() {
+		c.emit("monitorList", s.monitorList())
+		c.emit("notificationList", s.notificationList())
+	}
+	if s.listDelay > 0 {
+		time.AfterFunc(s.listDelay, sendLists)
+		return
+	}
+	sendLists()
+}
+
+func (s *Server) handleAdd(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var monitor map[string]interface{}
+	if len(args) < 1 || json.Unmarshal(args[0], &monitor) != nil || monitor == nil {
+		return errorResponse("Invalid monitor payload.")
+	}
+
+	s.mu.Lock()
+	id := s.storeMonitor(monitor)
+	s.mu.Unlock()
+
+	s.broadcastMonitorUpdate(id)
+
+	return map[string]interface{}{"ok": true, "msg": "Added Successfully.", "monitorID": id}
+}
+
+func (s *Server) handleEditMonitor(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var monitor map[string]interface{}
+	if len(args) < 1 || json.Unmarshal(args[0], &monitor) != nil || monitor == nil {
+		return errorResponse("Invalid monitor payload.")
+	}
+
+	id, ok := toInt(monitor["id"])
+	if !ok {
+		return errorResponse("Monitor ID is required.")
+	}
+
+	s.mu.Lock()
+	existing, exists := s.monitors[id]
+	if !exists {
+		s.mu.Unlock()
+		return errorResponse("Permission denied.")
+	}
+	for key, value := range monitor {
+		existing[key] = value
+	}
+	existing["id"] = id
+	s.mu.Unlock()
+
+	s.broadcastMonitorUpdate(id)
+
+	return map[string]interface{}{"ok": true, "msg": "Saved.", "monitorID": id}
+}
+
+func (s *Server) handleDeleteMonitor(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var id int
+	if len(args) < 1 || json.Unmarshal(args[0], &id) != nil {
+		return errorResponse("Invalid monitor ID.")
+	}
+
+	// The parent column is ON DELETE SET NULL, so the children of a group
+	// move to the top level
+	s.mu.Lock()
+	delete(s.monitors, id)
+	for _, monitor := range s.monitors {
+		if parent, ok := toInt(monitor["parent"]); ok && parent == id {
+			monitor["parent"] = nil
+		}
+	}
+	s.mu.Unlock()
+
+	// The monitor list is only refreshed after the deletion is acknowledged
+	return ackThen{
+		result: map[string]interface{}{"ok": true, "msg": "Deleted Successfully."},
+		then:   func() { s.broadcastMonitorDelete(id) },
+	}
+}
+
+func (s *Server) handleGetMonitorList(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	c.emit("monitorList", s.monitorList())
+
+	return map[string]interface{}{"ok": true}
+}
+
+func (s *Server) handleAddNotification(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var notification map[string]interface{}
+	if len(args) < 1 || json.Unmarshal(args[0], &notification) != nil || notification == nil {
+		return errorResponse("Invalid notification payload.")
+	}
+
+	// The second argument is the ID of the notification to update, or null
+	var id int
+	if len(args) > 1 {
+		json.Unmarshal(args[1], &id)
+	}
+
+	return s.saveNotification(notification, id)
+}
+
+func (s *Server) handleDeleteNotification(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var id int
+	if len(args) < 1 || json.Unmarshal(args[0], &id) != nil {
+		return errorResponse("Invalid notification ID.")
+	}
+
+	s.mu.Lock()
+	if _, exists := s.notifications[id]; !exists {
+		s.mu.Unlock()
+		return errorResponse("Cannot find notification")
+	}
+	delete(s.notifications, id)
+	s.mu.Unlock()
+
+	s.broadcastNotificationList()
+
+	return map[string]interface{}{"ok": true, "msg": "Deleted"}
+}
+
+func (s *Server) handleGetTags(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	s.mu.Lock()
+	tags := make([]interface{}, 0, len(s.tags))
+	for _, id := range sortedKeys(s.tags) {
+		tags = append(tags, copyMap(s.tags[id]))
+	}
+	s.mu.Unlock()
+
+	return map[string]interface{}{"ok": true, "tags": tags}
+}
+
+func (s *Server) handleAddTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var tag struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if len(args) < 1 || json.Unmarshal(args[0], &tag) != nil {
+		return errorResponse("Invalid tag payload.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.storeTag(tag.Name, tag.Color)
+
+	return map[string]interface{}{"ok": true, "tag": copyMap(s.tags[id])}
+}
+
+func (s *Server) handleEditTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var tag struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	if len(args) < 1 || json.Unmarshal(args[0], &tag) != nil {
+		return errorResponse("Invalid tag payload.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.tags[tag.ID]
+	if !ok {
+		return errorResponse("Tag not found")
+	}
+	existing["name"] = tag.Name
+	existing["color"] = tag.Color
+
+	return map[string]interface{}{"ok": true, "msg": "Saved", "tag": copyMap(existing)}
+}
+
+func (s *Server) handleDeleteTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var id int
+	if len(args) < 1 || json.Unmarshal(args[0], &id) != nil {
+		return errorResponse("Invalid tag ID.")
+	}
+
+	// Like Uptime Kuma, deleting a tag that does not exist succeeds. The
+	// assignments go with it.
+	s.mu.Lock()
+	delete(s.tags, id)
+	for _, monitor := range s.monitors {
+		filterMonitorTags(monitor, func(tag map[string]interface{}) bool {
+			return tag["tag_id"] != id
+		})
+	}
+	s.mu.Unlock()
+
+	return map[string]interface{}{"ok": true, "msg": "Deleted Successfully."}
+}
+
+// monitorTagArgs decodes the (tagID, monitorID, value) arguments of the
+// monitor tag events
+func monitorTagArgs(args []json.RawMessage) (tagID, monitorID int, value string, ok bool) {
+	if len(args) < 3 || json.Unmarshal(args[0], &tagID) != nil || json.Unmarshal(args[1], &monitorID) != nil {
+		return 0, 0, "", false
+	}
+	json.Unmarshal(args[2], &value)
+	return tagID, monitorID, value, true
+}
+
+// handleAddMonitorTag assigns a tag to a monitor. Like Uptime Kuma, it does
+// not push the monitor list.
+func (s *Server) handleAddMonitorTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	tagID, monitorID, value, ok := monitorTagArgs(args)
+	if !ok {
+		return errorResponse("Invalid monitor tag.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag, tagExists := s.tags[tagID]
+	monitor, monitorExists := s.monitors[monitorID]
+	if !tagExists || !monitorExists {
+		return errorResponse("SQLITE_CONSTRAINT: FOREIGN KEY constraint failed")
+	}
+
+	tags, _ := monitor["tags"].([]interface{})
+	monitor["tags"] = append(append([]interface{}(nil), tags...), map[string]interface{}{
+		"id":         s.nextMonitorTagID,
+		"monitor_id": monitorID,
+		"tag_id":     tagID,
+		"value":      value,
+		"name":       tag["name"],
+		"color":      tag["color"],
+	})
+	s.nextMonitorTagID++
+
+	return map[string]interface{}{"ok": true, "msg": "Added Successfully."}
+}
+
+// handleEditMonitorTag sets the value of every assignment of the tag to the
+// monitor
+func (s *Server) handleEditMonitorTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	tagID, monitorID, value, ok := monitorTagArgs(args)
+	if !ok {
+		return errorResponse("Invalid monitor tag.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if monitor, exists := s.monitors[monitorID]; exists {
+		tags, _ := monitor["tags"].([]interface{})
+		edited := make([]interface{}, len(tags))
+		for i, item := range tags {
+			tag := copyMap(item.(map[string]interface{}))
+			if tag["tag_id"] == tagID {
+				tag["value"] = value
+			}
+			edited[i] = tag
+		}
+		monitor["tags"] = edited
+	}
+
+	return map[string]interface{}{"ok": true, "msg": "Edited Successfully."}
+}
+
+// handleDeleteMonitorTag removes the assignments of the tag with the value
+// from the monitor
+func (s *Server) handleDeleteMonitorTag(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	tagID, monitorID, value, ok := monitorTagArgs(args)
+	if !ok {
+		return errorResponse("Invalid monitor tag.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if monitor, exists := s.monitors[monitorID]; exists {
+		filterMonitorTags(monitor, func(tag map[string]interface{}) bool {
+			return tag["tag_id"] != tagID || tag["value"] != value
+		})
+	}
+
+	return map[string]interface{}{"ok": true, "msg": "Deleted Successfully."}
+}
+
+// filterMonitorTags keeps the tag assignments of a monitor accepted by keep.
+// Callers must hold s.mu.
+func filterMonitorTags(monitor map[string]interface{}, keep func(map[string]interface{}) bool) {
+	tags, _ := monitor["tags"].([]interface{})
+	kept := make([]interface{}, 0, len(tags))
+	for _, item := range tags {
+		if tag, ok := item.(map[string]interface{}); ok && keep(tag) {
+			kept = append(kept, tag)
+		}
+	}
+	monitor["tags"] = kept
+}
+
+// statusPage is a stored status page
+type statusPage struct {
+	config map[string]interface{}   // Keyed like StatusPage.toJSON()
+	groups []map[string]interface{} // id, name and monitorList of {id, sendUrl}
+}
+
+// slugPattern is the slug format accepted by Uptime Kuma
+var slugPattern = regexp.MustCompile(`^[A-Za-z0-9]+(?:-[A-Za-z0-9]+)*$`)
+
+func (s *Server) handleAddStatusPage(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var title, slug string
+	if len(args) < 2 || json.Unmarshal(args[0], &title) != nil || json.Unmarshal(args[1], &slug) != nil {
+		return errorResponse("Invalid status page.")
+	}
+	title, slug = strings.TrimSpace(title), strings.TrimSpace(slug)
+	if title == "" {
+		return errorResponse("Please input title")
+	}
+	if !slugPattern.MatchString(slug) {
+		return errorResponse("Invalid Slug")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.statusPages[slug]; exists {
+		return errorResponse("SQLITE_CONSTRAINT: UNIQUE constraint failed: status_page.slug")
+	}
+	s.storeStatusPage(slug, title)
+
+	return map[string]interface{}{"ok": true, "msg": "OK"}
+}
+
+func (s *Server) handleGetStatusPage(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var slug string
+	if len(args) > 0 {
+		json.Unmarshal(args[0], &slug)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, ok := s.statusPages[slug]
+	if !ok {
+		return errorResponse("No slug?")
+	}
+	return map[string]interface{}{"ok": true, "config": statusPageJSON(page.config)}
+}
+
+// handleSaveStatusPage saves the configuration and replaces the public
+// groups the way Uptime Kuma does: groups without a known ID are created and
+// groups left out are deleted
+func (s *Server) handleSaveStatusPage(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var slug, imgDataURL string
+	var config map[string]interface{}
+	var groups []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		MonitorList []struct {
+			ID      int   `json:"id"`
+			SendURL *bool `json:"sendUrl"`
+		} `json:"monitorList"`
+	}
+	if len(args) < 4 || json.Unmarshal(args[0], &slug) != nil || json.Unmarshal(args[1], &config) != nil ||
+		json.Unmarshal(args[2], &imgDataURL) != nil || json.Unmarshal(args[3], &groups) != nil {
+		return errorResponse("Invalid status page.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page, ok := s.statusPages[slug]
+	if !ok {
+		return errorResponse("No slug?")
+	}
+	newSlug, _ := config["slug"].(string)
+	if !slugPattern.MatchString(newSlug) {
+		return errorResponse("Invalid Slug")
+	}
+	if _, taken := s.statusPages[newSlug]; taken && newSlug != slug {
+		return errorResponse("SQLITE_CONSTRAINT: UNIQUE constraint failed: status_page.slug")
+	}
+
+	icon := imgDataURL
+	if strings.HasPrefix(imgDataURL, "data:") {
+		if !strings.HasPrefix(imgDataURL, "data:image/png;base64,") {
+			return errorResponse("Only allowed PNG logo.")
+		}
+		icon = fmt.Sprintf("/upload/logo%d.png?t=%d", page.config["id"], time.Now().UnixMilli())
+	}
+
+	for _, key := range []string{"title", "description", "theme", "showTags", "domainNameList", "customCSS", "footerText", "showPoweredBy", "showCertificateExpiry", "googleAnalyticsId"} {
+		page.config[key] = config[key]
+	}
+	page.config["slug"] = newSlug
+	page.config["icon"] = icon
+
+	existing := make(map[int]bool, len(page.groups))
+	for _, group := range page.groups {
+		existing[group["id"].(int)] = true
+	}
+	saved := make([]map[string]interface{}, len(groups))
+	result := make([]interface{}, len(groups))
+	for i, group := range groups {
+		id := group.ID
+		if !existing[id] {
+			id = s.nextGroupID
+			s.nextGroupID++
+		}
+		monitors := make([]interface{}, len(group.MonitorList))
+		for j, monitor := range group.MonitorList {
+			entry := map[string]interface{}{"id": monitor.ID}
+			if monitor.SendURL != nil {
+				entry["sendUrl"] = *monitor.SendURL
+			}
+			monitors[j] = entry
+		}
+		saved[i] = map[string]interface{}{"id": id, "name": group.Name, "monitorList": monitors}
+		result[i] = copyMap(saved[i])
+	}
+	page.groups = saved
+
+	delete(s.statusPages, slug)
+	s.statusPages[newSlug] = page
+
+	return map[string]interface{}{"ok": true, "publicGroupList": result}
+}
+
+func (s *Server) handleDeleteStatusPage(c *conn, args []json.RawMessage) interface{} {
+	if !c.isLoggedIn() {
+		return errorResponse("You are not logged in.")
+	}
+
+	var slug string
+	if len(args) > 0 {
+		json.Unmarshal(args[0], &slug)
+	}
+
+	// Like Uptime Kuma, deleting a page that does not exist succeeds
+	s.mu.Lock()
+	delete(s.statusPages, slug)
+	s.mu.Unlock()
+
+	return map[string]interface{}{"ok": true}
+}
+
+// storeStatusPage creates a status page with the defaults of addStatusPage.
+// Callers must hold s.mu.
+func (s *Server) storeStatusPage(slug, title string) {
+	s.statusPages[slug] = &statusPage{
+		config: map[string]interface{}{
+			"id":                    s.nextStatusPageID,
+			"slug":                  slug,
+			"title":                 title,
+			"description":           nil,
+			"icon":                  "",
+			"theme":                 "auto",
+			"published":             true,
+			"showTags":              false,
+			"domainNameList":        []interface{}{},
+			"customCSS":             nil,
+			"footerText":            nil,
+			"showPoweredBy":         true,
+			"showCertificateExpiry": false,
+			"googleAnalyticsId":     nil,
+		},
+	}
+	s.nextStatusPageID++
+}
+
+// statusPageJSON renders a stored configuration like StatusPage.toJSON(),
+// which falls back to the default icon
+func statusPageJSON(config map[string]interface{}) map[string]interface{} {
+	out := copyMap(config)
+	if icon, _ := out["icon"].(string); icon == "" {
+		out["icon"] = "/icon.svg"
+	}
+	return out
+}
+
+// publicGroupJSON renders a public group like Group.toPublicJSON(), with the
+// monitor names and sendUrl as the 0/1 SQLite stores. Callers must hold s.mu.
+func (s *Server) publicGroupJSON(group map[string]interface{}) map[string]interface{} {
+	entries, _ := group["monitorList"].([]interface{})
+	monitors := make([]interface{}, 0, len(entries))
+	for _, item := range entries {
+		entry := item.(map[string]interface{})
+		id := entry["id"].(int)
+		sendURL := 0
+		if send, _ := entry["sendUrl"].(bool); send {
+			sendURL = 1
+		}
+		name, _ := s.monitors[id]["name"].(string)
+		monitors = append(monitors, map[string]interface{}{"id": id, "name": name, "sendUrl": sendURL, "type": s.monitors[id]["type"]})
+	}
+	return map[string]interface{}{"id": group["id"], "name": group["name"], "monitorList": monitors}
+}
+
+// storeTag assigns an ID to a new tag and stores it. Callers must hold s.mu.
+func (s *Server) storeTag(name, color string) int {
+	id := s.nextTagID
+	s.nextTagID++
+
+	s.tags[id] = map[string]interface{}{
+		"id":    id,
+		"name":  name,
+		"color": color,
+	}
+	return id
+}
+
+// saveNotification creates or updates a notification the way Uptime Kuma's
+// Notification.save does: the whole payload is stored as the JSON config
+func (s *Server) saveNotification(notification map[string]interface{}, id int) interface{} {
+	s.mu.Lock()
+	if id != 0 {
+		if _, exists := s.notifications[id]; !exists {
+			s.mu.Unlock()
+			return errorResponse("Cannot find notification")
+		}
+	} else {
+		id = s.nextNotificationID
+		s.nextNotificationID++
+	}
+
+	delete(notification, "id")
+	config, _ := json.Marshal(notification)
+
+	name, _ := notification["name"].(string)
+	isDefault, _ := notification["isDefault"].(bool)
+
+	s.notifications[id] = map[string]interface{}{
+		"id":        id,
+		"name":      name,
+		"active":    true,
+		"userId":    1,
+		"isDefault": isDefault,
+		"config":    string(config),
+	}
+	s.mu.Unlock()
+
+	s.broadcastNotificationList()
+
+	return map[string]interface{}{"ok": true, "msg": "Saved", "id": id}
+}
+
+// storeMonitor assigns an ID to the monitor and stores it. Callers must hold s.mu.
+func (s *Server) storeMonitor(monitor map[string]interface{}) int {
+	id := s.nextMonitorID
+	s.nextMonitorID++
+
+	monitor["id"] = id
+	if _, ok := monitor["tags"]; !ok {
+		monitor["tags"] = []interface{}{}
+	}
+	s.monitors[id] = monitor
+
+	return id
+}
+
+// monitorList builds the monitorList event payload, keyed by monitor ID
+func (s *Server) monitorList() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make(map[string]interface{}, len(s.monitors))
+	for id, monitor := range s.monitors {
+		list[strconv.Itoa(id)] = copyMap(monitor)
+	}
+	return list
+}
+
+// notificationList builds the notificationList event payload
+func (s *Server) notificationList() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]interface{}, 0, len(s.notifications))
+	for _, id := range sortedKeys(s.notifications) {
+		list = append(list, copyMap(s.notifications[id]))
+	}
+	return list
+}
+
+// incrementalMonitorList reports whether the server behaves like Uptime Kuma
+// 2.0, which updates the monitor list of clients with updateMonitorIntoList
+// and deleteMonitorFromList instead of sending it again after every change
+func (s *Server) incrementalMonitorList() bool {
+	major, _ := strconv.Atoi(strings.SplitN(s.version, ".", 2)[0])
+	return major >= 2
+}
+
+// broadcastMonitorUpdate tells every logged in client that a monitor was
+// added or changed
+func (s *Server) broadcastMonitorUpdate(id int) {
+	if s.listDelay > 0 {
+		time.AfterFunc(s.listDelay, func() { s.sendMonitorUpdate(id) })
+		return
+	}
+	s.sendMonitorUpdate(id)
+}
+
+// sendMonitorUpdate sends the update of broadcastMonitorUpdate
+func (s *Server) sendMonitorUpdate(id int) {
+	if !s.incrementalMonitorList() {
+		s.broadcastMonitorList()
+		return
+	}
+
+	monitor, ok := s.monitorList()[strconv.Itoa(id)]
+	if !ok {
+		return
+	}
+	update := map[string]interface{}{strconv.Itoa(id): monitor}
+	for _, c := range s.loggedInConns() {
+		c.emit("updateMonitorIntoList", update)
+	}
+}
+
+// broadcastMonitorDelete tells every logged in client that a monitor was
+// deleted
+func (s *Server) broadcastMonitorDelete(id int) {
+	if s.listDelay > 0 {
+		time.AfterFunc(s.listDelay, func() { s.sendMonitorDelete(id) })
+		return
+	}
+	s.sendMonitorDelete(id)
+}
+
+// sendMonitorDelete sends the update of broadcastMonitorDelete
+func (s *Server) sendMonitorDelete(id int) {
+	if !s.incrementalMonitorList() {
+		s.broadcastMonitorList()
+		return
+	}
+
+	for _, c := range s.loggedInConns() {
+		c.emit("deleteMonitorFromList", id)
+	}
+}
+
+// broadcastMonitorList pushes the monitor list to every logged in client
+func (s *Server) broadcastMonitorList() {
+	list := s.monitorList()
+	for _, c := range s.loggedInConns() {
+		c.emit("monitorList", list)
+	}
+}
+
+// broadcastNotificationList pushes the notification list to every logged in client
+func (s *Server) broadcastNotificationList() {
+	list := s.notificationList()
+	for _, c := range s.loggedInConns() {
+		c.emit("notificationList", list)
+	}
+}
+
+func (s *Server) loggedInConns() []*conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var conns []*conn
+	for c := range s.conns {
+		if c.isLoggedIn() {
+			conns = append(conns, c)
+		}
+	}
+	return conns
+}
+
+// conn is a single client connection
+type conn struct {
+	server *Server
+	ws     *websocket.Conn
+	sid    string
+
+	writeMu sync.Mutex
+
+	stateMu   sync.Mutex
+	loggedIn  bool
+	closeOnce sync.Once
+
+	pong chan struct{}
+	done chan struct{}
+}
+
+// handlePacket processes one Engine.IO packet
+func (c *conn) handlePacket(packet string) {
+	if packet == "" {
+		return
+	}
+
+	switch packet[0] {
+	case '3': // pong
+		select {
+		case c.pong <- struct{}{}:
+		default:
+		}
+	case '4': // message
+		c.handleSocketIO(packet[1:])
+	}
+}
+
+// handleSocketIO processes one Socket.IO packet on the default namespace
+func (c *conn) handleSocketIO(packet string) {
+	if packet == "" {
+		return
+	}
+
+	switch packet[0] {
+	case '0': // connect
+		ack, _ := json.Marshal(map[string]string{"sid": c.sid})
+		c.write("40" + string(ack))
+	case '1': // disconnect
+		c.close()
+	case '2': // event
+		body := packet[1:]
+		i := strings.IndexByte(body, '[')
+		if i < 0 {
+			return
+		}
+		ackID := body[:i]
+
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(body[i:]), &raw); err != nil || len(raw) == 0 {
+			return
+		}
+		var event string
+		if err := json.Unmarshal(raw[0], &event); err != nil {
+			return
+		}
+
+		c.server.handleEvent(c, ackID, event, raw[1:])
+	}
+}
+
+// heartbeat sends Engine.IO pings and closes the connection when a pong is
+// not received within the ping timeout
+func (c *conn) heartbeat(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		c.server.mu.Lock()
+		paused := c.server.pingsPaused
+		c.server.mu.Unlock()
+		if paused {
+			continue
+		}
+
+		if err := c.write("2"); err != nil {
+			return
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-c.pong:
+		case <-time.After(timeout):
+			c.close()
+			return
+		}
+	}
+}
+
+// emit sends a Socket.IO event without an acknowledgement
+func (c *conn) emit(event string, args ...interface{}) error {
+	payload, err := json.Marshal(append([]interface{}{event}, args...))
+	if err != nil {
+		return err
+	}
+	return c.write("42" + string(payload))
+}
+
+func (c *conn) write(message string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.ws.WriteMessage(websocket.TextMessage, []byte(message))
+}
+
+func (c *conn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
+
+func (c *conn) setLoggedIn() {
+	c.stateMu.Lock()
+	c.loggedIn = true
+	c.stateMu.Unlock()
+}
+
+func (c *conn) isLoggedIn() bool {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	return c.loggedIn
+}
+
+func errorResponse(msg string) map[string]interface{} {
+	return map[string]interface{}{"ok": false, "msg": msg}
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys(m map[int]map[string]interface{}) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -19,7 +20,7 @@ func NewMonitorDataSource() datasource.DataSource {
 
 // MonitorDataSource defines the data source implementation.
 type MonitorDataSource struct {
-	client *Client
+	client *uptimekuma.Client
 }
 
 // MonitorDataSourceModel describes the data source data model.
@@ -119,7 +120,7 @@ func (d *MonitorDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				Computed:            true,
 			},
 			"tags": schema.ListAttribute{
-				MarkdownDescription: "List of tags",
+				MarkdownDescription: "Names of the tags assigned to the monitor",
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
@@ -153,12 +154,12 @@ func (d *MonitorDataSource) Configure(ctx context.Context, req datasource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(*uptimekuma.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *uptimekuma.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -168,6 +169,10 @@ func (d *MonitorDataSource) Configure(ctx context.Context, req datasource.Config
 }
 
 func (d *MonitorDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !clientConfigured(d.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data MonitorDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -185,7 +190,7 @@ func (d *MonitorDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	// Get monitor from API
-	monitor, err := d.client.GetMonitor(id)
+	monitor, err := d.client.GetMonitor(ctx, id)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read monitor, got error: %s", err))
 		return
@@ -219,7 +224,11 @@ func (d *MonitorDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	if len(monitor.Tags) > 0 {
-		tagsList, diags := types.ListValueFrom(ctx, types.StringType, monitor.Tags)
+		tagNames := make([]string, len(monitor.Tags))
+		for i, tag := range monitor.Tags {
+			tagNames[i] = tag.Name
+		}
+		tagsList, diags := types.ListValueFrom(ctx, types.StringType, tagNames)
 		resp.Diagnostics.Append(diags...)
 		data.Tags = tagsList
 	}
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -13,13 +16,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &MonitorResource{}
 var _ resource.ResourceWithImportState = &MonitorResource{}
+var _ resource.ResourceWithModifyPlan = &MonitorResource{}
 
 func NewMonitorResource() resource.Resource {
 	return &MonitorResource{}
@@ -27,7 +33,7 @@ func NewMonitorResource() resource.Resource {
 
 // MonitorResource defines the resource implementation.
 type MonitorResource struct {
-	client *Client
+	client *uptimekuma.Client
 }
 
 // MonitorResourceModel describes the resource data model.
@@ -49,6 +55,8 @@ type MonitorResourceModel struct {
 	FollowRedirect        types.Bool   `tfsdk:"follow_redirect"`
 	Tags                  types.List   `tfsdk:"tags"`
 	NotificationIDList    types.List   `tfsdk:"notification_id_list"`
+	Tag                   types.Set    `tfsdk:"tag"`
+	ParentID              types.Int64  `tfsdk:"parent_id"`
 	Active                types.Bool   `tfsdk:"active"`
 	IgnoreTLS             types.Bool   `tfsdk:"ignore_tls"`
 	HTTPMethod            types.String `tfsdk:"http_method"`
@@ -57,6 +65,18 @@ type MonitorResourceModel struct {
 	BasicAuthPass         types.String `tfsdk:"basic_auth_pass"`
 }
 
+// MonitorTagModel describes a tag block of a monitor.
+type MonitorTagModel struct {
+	TagID types.Int64  `tfsdk:"tag_id"`
+	Value types.String `tfsdk:"value"`
+}
+
+// monitorTagAttrTypes are the attribute types of a tag block
+var monitorTagAttrTypes = map[string]attr.Type{
+	"tag_id": types.Int64Type,
+	"value":  types.StringType,
+}
+
 func (r *MonitorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_monitor"
 }
@@ -79,7 +99,7 @@ func (r *MonitorResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Required:            true,
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "Monitor type (http, tcp, ping, etc.)",
+				MarkdownDescription: "Monitor type (http, tcp, ping, group, etc.)",
 				Optional:            true,
 				Computed:            true,
 				Default:             stringdefault.StaticString("http"),
@@ -146,9 +166,14 @@ func (r *MonitorResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Optional:            true,
 			},
 			"tags": schema.ListAttribute{
-				MarkdownDescription: "List of tags",
+				MarkdownDescription: "List of tags. Never sent to Uptime Kuma; use `tag` blocks instead",
 				Optional:            true,
 				ElementType:         types.StringType,
+				DeprecationMessage:  "The tags attribute is ignored. Assign tags with tag blocks referencing uptimekuma_tag resources instead.",
+			},
+			"parent_id": schema.Int64Attribute{
+				MarkdownDescription: "ID of the group monitor this monitor is nested in; unset keeps it at the top level",
+				Optional:            true,
 			},
 			"notification_id_list": schema.ListAttribute{
 				MarkdownDescription: "List of notification IDs to associate with this monitor",
@@ -183,6 +208,26 @@ func (r *MonitorResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Sensitive:           true,
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"tag": schema.SetNestedBlock{
+				MarkdownDescription: "Tag assigned to the monitor, optionally with a value. The same tag may be assigned more than once with different values.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"tag_id": schema.Int64Attribute{
+							MarkdownDescription: "ID of the tag, e.g. from an uptimekuma_tag resource",
+							Required:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "Tag value (e.g., payments for a team tag)",
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(""),
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -192,12 +237,12 @@ func (r *MonitorResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(*uptimekuma.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *uptimekuma.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -206,7 +251,139 @@ func (r *MonitorResource) Configure(ctx context.Context, req resource.ConfigureR
 	r.client = client
 }
 
+// monitorAttributeVersions lists the Uptime Kuma release that introduced the
+// monitor field behind each resource attribute newer than 1.22. Monitor
+// groups, and with them parent_id, arrived in 1.23 as well.
+var monitorAttributeVersions = map[string]string{
+	"timeout":   "1.23.0",
+	"parent_id": "1.23.0",
+}
+
+// versionRequirement is a monitor type or attribute in a configuration that
+// needs a minimum Uptime Kuma version
+type versionRequirement struct {
+	attribute  string
+	summary    string
+	feature    string
+	minVersion string
+}
+
+// monitorVersionRequirements returns the version requirements of the monitor
+// types and attributes used in a configuration. Attributes with defaults only
+// count when they are set explicitly.
+func monitorVersionRequirements(config MonitorResourceModel) []versionRequirement {
+	var requirements []versionRequirement
+
+	if !config.Type.IsNull() && !config.Type.IsUnknown() {
+		monitorType := config.Type.ValueString()
+		if minVersion, ok := uptimekuma.MonitorTypeVersion(monitorType); ok {
+			requirements = append(requirements, versionRequirement{"type", "Unsupported Monitor Type", fmt.Sprintf("Monitor type %q", monitorType), minVersion})
+		}
+	}
+
+	configured := map[string]bool{
+		"timeout":   !config.Timeout.IsNull(),
+		"parent_id": !config.ParentID.IsNull(),
+	}
+	for _, attribute := range []string{"timeout", "parent_id"} {
+		if configured[attribute] {
+			requirements = append(requirements, versionRequirement{attribute, "Unsupported Monitor Attribute", fmt.Sprintf("The %s attribute", attribute), monitorAttributeVersions[attribute]})
+		}
+	}
+
+	return requirements
+}
+
+// checkVersionRequirements adds an error for every requirement the
+// connected Uptime Kuma version does not meet
+func (r *MonitorResource) checkVersionRequirements(requirements []versionRequirement, diags *diag.Diagnostics) {
+	for _, requirement := range requirements {
+		if err := r.client.CheckVersion(requirement.feature, requirement.minVersion); err != nil {
+			diags.AddAttributeError(path.Root(requirement.attribute), requirement.summary, err.Error())
+		}
+	}
+}
+
+// ModifyPlan rejects monitor types and attributes the connected Uptime Kuma
+// version does not support, and parents that are not groups or would nest a
+// monitor inside itself, so the error shows up at plan time. It only checks
+// when the client is already connected, e.g. by refreshing other resources,
+// so planning new monitors never waits for an unreachable server; Create
+// and Update repeat the checks after connecting.
+func (r *MonitorResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var config MonitorResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A parent created in the same plan is unknown and cannot be checked yet
+	checkParent := !config.ParentID.IsNull() && !config.ParentID.IsUnknown()
+	requirements := monitorVersionRequirements(config)
+	if len(requirements) == 0 && !checkParent {
+		return
+	}
+
+	if !r.client.Connected() {
+		tflog.Debug(ctx, "Uptime Kuma is not connected yet, leaving the monitor checks to apply")
+		return
+	}
+
+	r.checkVersionRequirements(requirements, &resp.Diagnostics)
+
+	if checkParent {
+		var id int
+		if !req.State.Raw.IsNull() {
+			var stateID types.String
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &stateID)...)
+			id, _ = strconv.Atoi(stateID.ValueString())
+		}
+		if err := r.client.CheckParent(id, int(config.ParentID.ValueInt64())); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("parent_id"), "Invalid Monitor Parent", err.Error())
+		}
+	}
+}
+
+// checkSupport connects and rejects monitor types and attributes of the
+// configuration the server does not support, for plans made while it was
+// not reachable
+func (r *MonitorResource) checkSupport(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	if config.Raw.IsNull() {
+		return
+	}
+
+	var data MonitorResourceModel
+	diags.Append(config.Get(ctx, &data)...)
+	if diags.HasError() {
+		return
+	}
+
+	requirements := monitorVersionRequirements(data)
+	if len(requirements) == 0 {
+		return
+	}
+	if err := r.client.Connect(ctx); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to connect to Uptime Kuma, got error: %s", err))
+		return
+	}
+	r.checkVersionRequirements(requirements, diags)
+}
+
 func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	r.checkSupport(ctx, req.Config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data MonitorResourceModel
 
 	// Read Terraform plan data into the model
@@ -217,7 +394,7 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// Convert Terraform model to API model
-	monitor := &Monitor{
+	monitor := &uptimekuma.Monitor{
 		Name:           data.Name.ValueString(),
 		Type:           data.Type.ValueString(),
 		URL:            data.URL.ValueString(),
@@ -237,6 +414,7 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 		Body:           data.Body.ValueString(),
 		BasicAuthUser:  data.BasicAuthUser.ValueString(),
 		BasicAuthPass:  data.BasicAuthPass.ValueString(),
+		Parent:         int(data.ParentID.ValueInt64()),
 	}
 
 	// Convert lists
@@ -246,12 +424,6 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 		monitor.AcceptedStatusCodes = statusCodes
 	}
 
-	if !data.Tags.IsNull() {
-		var tags []string
-		data.Tags.ElementsAs(ctx, &tags, false)
-		monitor.Tags = tags
-	}
-
 	if !data.NotificationIDList.IsNull() {
 		var notificationIDs []string
 		data.NotificationIDList.ElementsAs(ctx, &notificationIDs, false)
@@ -262,8 +434,14 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 		}
 	}
 
+	tags, diags := monitorTagsFromModel(ctx, data.Tag)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create new monitor
-	createdMonitor, err := r.client.CreateMonitor(monitor)
+	createdMonitor, err := r.client.CreateMonitor(ctx, monitor)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create monitor, got error: %s", err))
 		return
@@ -273,6 +451,14 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 	// Update the model with the created monitor ID
 	data.ID = types.StringValue(strconv.Itoa(createdMonitor.ID))
 
+	// Assign tags; on failure the monitor is still saved so the next apply
+	// replaces it instead of leaving it behind
+	if len(tags) > 0 {
+		if err := r.client.SetMonitorTags(ctx, createdMonitor.ID, tags); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to assign tags to monitor, got error: %s", err))
+		}
+	}
+
 	// Don't read back from server - preserve plan values to avoid inconsistent state errors
 	// The state should reflect what we sent to the API
 
@@ -284,6 +470,10 @@ func (r *MonitorResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (r *MonitorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data MonitorResourceModel
 
 	// Read Terraform prior state data into the model
@@ -301,16 +491,17 @@ func (r *MonitorResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	// Refresh monitor data from the API to ensure we have the latest state
-	err = r.client.RefreshMonitors()
+	err = r.client.RefreshMonitors(ctx)
 	if err != nil {
 		tflog.Warn(ctx, fmt.Sprintf("Unable to refresh monitors: %s", err))
 	}
 
 	// Get monitor from API
-	monitor, err := r.client.GetMonitor(id)
+	monitor, err := r.client.GetMonitor(ctx, id)
 	if err != nil {
 		// If the monitor is not found, remove it from state (Terraform will recreate it)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, uptimekuma.ErrNotFound) {
+			tflog.Warn(ctx, fmt.Sprintf("Monitor %d no longer exists, removing it from state", id))
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -332,6 +523,11 @@ func (r *MonitorResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if monitor.Port != 0 {
 		data.Port = types.Int64Value(int64(monitor.Port))
 	}
+	if monitor.Parent != 0 {
+		data.ParentID = types.Int64Value(int64(monitor.Parent))
+	} else {
+		data.ParentID = types.Int64Null()
+	}
 
 	data.Interval = types.Int64Value(int64(monitor.Interval))
 	data.Timeout = types.Int64Value(int64(monitor.Timeout))
@@ -400,11 +596,31 @@ func (r *MonitorResource) Read(ctx context.Context, req resource.ReadRequest, re
 		}
 	}
 
+	// Tags are always read back so assignments changed in the UI show up
+	// as drift; a monitor without tags keeps a null set if it had one
+	if len(monitor.Tags) > 0 || !data.Tag.IsNull() {
+		tagSet, diags := monitorTagsToModel(ctx, monitor.Tags)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Tag = tagSet
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	r.checkSupport(ctx, req.Config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data MonitorResourceModel
 
 	// Read Terraform plan data into the model
@@ -422,7 +638,7 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Convert Terraform model to API model
-	monitor := &Monitor{
+	monitor := &uptimekuma.Monitor{
 		ID:             id,
 		Name:           data.Name.ValueString(),
 		Type:           data.Type.ValueString(),
@@ -443,6 +659,7 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 		Body:           data.Body.ValueString(),
 		BasicAuthUser:  data.BasicAuthUser.ValueString(),
 		BasicAuthPass:  data.BasicAuthPass.ValueString(),
+		Parent:         int(data.ParentID.ValueInt64()),
 	}
 
 	// Convert lists
@@ -452,12 +669,6 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 		monitor.AcceptedStatusCodes = statusCodes
 	}
 
-	if !data.Tags.IsNull() {
-		var tags []string
-		data.Tags.ElementsAs(ctx, &tags, false)
-		monitor.Tags = tags
-	}
-
 	if !data.NotificationIDList.IsNull() {
 		var notificationIDs []string
 		data.NotificationIDList.ElementsAs(ctx, &notificationIDs, false)
@@ -468,13 +679,26 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 		}
 	}
 
+	tags, diags := monitorTagsFromModel(ctx, data.Tag)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Update monitor
-	_, err = r.client.UpdateMonitor(monitor)
+	_, err = r.client.UpdateMonitor(ctx, monitor)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update monitor, got error: %s", err))
 		return
 	}
 
+	// Reconcile tags, adding, changing and removing only what differs
+	err = r.client.SetMonitorTags(ctx, id, tags)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update monitor tags, got error: %s", err))
+		return
+	}
+
 	// Don't read back from server - preserve plan values to avoid inconsistent state errors
 	// The state should reflect what we sent to the API
 
@@ -485,7 +709,42 @@ func (r *MonitorResource) Update(ctx context.Context, req resource.UpdateRequest
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// monitorTagsFromModel converts the tag blocks of a plan
+func monitorTagsFromModel(ctx context.Context, set types.Set) ([]uptimekuma.MonitorTag, diag.Diagnostics) {
+	if set.IsNull() || set.IsUnknown() {
+		return nil, nil
+	}
+
+	var models []MonitorTagModel
+	diags := set.ElementsAs(ctx, &models, false)
+
+	tags := make([]uptimekuma.MonitorTag, len(models))
+	for i, model := range models {
+		tags[i] = uptimekuma.MonitorTag{
+			TagID: int(model.TagID.ValueInt64()),
+			Value: model.Value.ValueString(),
+		}
+	}
+	return tags, diags
+}
+
+// monitorTagsToModel converts the tags of a monitor to tag blocks
+func monitorTagsToModel(ctx context.Context, tags []uptimekuma.MonitorTag) (types.Set, diag.Diagnostics) {
+	models := make([]MonitorTagModel, len(tags))
+	for i, tag := range tags {
+		models[i] = MonitorTagModel{
+			TagID: types.Int64Value(int64(tag.TagID)),
+			Value: types.StringValue(tag.Value),
+		}
+	}
+	return types.SetValueFrom(ctx, types.ObjectType{AttrTypes: monitorTagAttrTypes}, models)
+}
+
 func (r *MonitorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data MonitorResourceModel
 
 	// Read Terraform prior state data into the model
@@ -502,15 +761,36 @@ func (r *MonitorResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	// Uptime Kuma moves the monitors inside a group to the top level instead
+	// of deleting them. Monitors managed here that referenced the group are
+	// normally destroyed or updated first, so any left are reported.
+	children, err := r.client.ChildMonitors(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read monitors, got error: %s", err))
+		return
+	}
+	if len(children) > 0 {
+		names := make([]string, len(children))
+		for i, child := range children {
+			names[i] = fmt.Sprintf("%q (%d)", child.Name, child.ID)
+		}
+		resp.Diagnostics.AddWarning("Monitor Group Not Empty",
+			fmt.Sprintf("Monitor group %d still contains %s. Uptime Kuma moves them to the top level when the group is deleted.", id, strings.Join(names, ", ")))
+	}
+
 	// Delete monitor
-	err = r.client.DeleteMonitor(id)
+	err = r.client.DeleteMonitor(ctx, id)
+	if errors.Is(err, uptimekuma.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Monitor %d was already deleted", id))
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete monitor, got error: %s", err))
 		return
 	}
 
 	// Refresh the monitor cache to ensure the deleted monitor is removed
-	err = r.client.RefreshMonitors()
+	err = r.client.RefreshMonitors(ctx)
 	if err != nil {
 		tflog.Warn(ctx, fmt.Sprintf("Unable to refresh monitors after delete: %s", err))
 	}
@@ -520,6 +800,10 @@ func (r *MonitorResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *MonitorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	// Validate that the ID is a valid integer
 	_, err := strconv.Atoi(req.ID)
 	if err != nil {
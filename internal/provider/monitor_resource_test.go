@@ -1,19 +1,30 @@
 package provider
 
 import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 func TestAccMonitorResource(t *testing.T) {
+	server := fakekuma.NewServer(t)
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccMonitorResourceConfig("test-monitor"),
+				Config: testAccMonitorResourceConfig(server, "test-monitor"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("uptimekuma_monitor.test", "name", "test-monitor"),
 					resource.TestCheckResourceAttr("uptimekuma_monitor.test", "type", "http"),
@@ -29,7 +40,7 @@ func TestAccMonitorResource(t *testing.T) {
 			},
 			// Update and Read testing
 			{
-				Config: testAccMonitorResourceConfig("test-monitor-updated"),
+				Config: testAccMonitorResourceConfig(server, "test-monitor-updated"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("uptimekuma_monitor.test", "name", "test-monitor-updated"),
 				),
@@ -39,22 +50,571 @@ func TestAccMonitorResource(t *testing.T) {
 	})
 }
 
-func testAccMonitorResourceConfig(name string) string {
-	return `
-provider "uptimekuma" {
-  url      = "http://localhost:3001"
-  username = "admin"
-  password = "test123"
-}
-
+func testAccMonitorResourceConfig(server *fakekuma.Server, name string) string {
+	return testAccProviderConfig(server) + `
 resource "uptimekuma_monitor" "test" {
   name = "` + name + `"
   type = "http"
   url  = "https://example.com"
-  
+
   interval = 60
   timeout  = 30
   active   = true
 }
 `
 }
+
+// testMonitorModel returns a fully known monitor model, as produced by a plan
+func testMonitorModel(name string) MonitorResourceModel {
+	return MonitorResourceModel{
+		ID:                  types.StringUnknown(),
+		Name:                types.StringValue(name),
+		Type:                types.StringValue("http"),
+		URL:                 types.StringValue("https://example.com"),
+		Hostname:            types.StringNull(),
+		Port:                types.Int64Null(),
+		Interval:            types.Int64Value(60),
+		Timeout:             types.Int64Value(30),
+		RetryInterval:       types.Int64Value(60),
+		ResendInterval:      types.Int64Value(0),
+		MaxRetries:          types.Int64Value(3),
+		UpsideDown:          types.BoolNull(),
+		MaxRedirects:        types.Int64Value(10),
+		AcceptedStatusCodes: types.ListNull(types.StringType),
+		FollowRedirect:      types.BoolNull(),
+		Tags:                types.ListNull(types.StringType),
+		NotificationIDList:  types.ListNull(types.StringType),
+		Tag:                 types.SetNull(types.ObjectType{AttrTypes: monitorTagAttrTypes}),
+		ParentID:            types.Int64Null(),
+		Active:              types.BoolValue(true),
+		IgnoreTLS:           types.BoolNull(),
+		HTTPMethod:          types.StringValue("GET"),
+		Body:                types.StringNull(),
+		BasicAuthUser:       types.StringNull(),
+		BasicAuthPass:       types.StringNull(),
+	}
+}
+
+func TestMonitorResource_CRUD(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &MonitorResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	// Create
+	plan := testMonitorModel("test-monitor")
+	createResp := fwresource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created MonitorResourceModel
+	createResp.State.Get(ctx, &created)
+	id, err := strconv.Atoi(created.ID.ValueString())
+	if err != nil {
+		t.Fatalf("unexpected monitor ID %q", created.ID.ValueString())
+	}
+	if stored, ok := server.Monitor(id); !ok || stored["name"] != "test-monitor" {
+		t.Fatalf("monitor %d not stored on server: %v", id, stored)
+	}
+
+	// Read
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	var read MonitorResourceModel
+	readResp.State.Get(ctx, &read)
+	if read.Name.ValueString() != "test-monitor" || read.URL.ValueString() != "https://example.com" {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	// Update
+	plan = read
+	plan.Name = types.StringValue("test-monitor-updated")
+	updateResp := fwresource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: testPlan(t, s, &plan), State: readResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+	if stored, _ := server.Monitor(id); stored["name"] != "test-monitor-updated" {
+		t.Errorf("expected updated name on server, got %v", stored["name"])
+	}
+
+	// Delete
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+	}
+	if _, ok := server.Monitor(id); ok {
+		t.Errorf("monitor %d still stored on server", id)
+	}
+
+	// Read after delete removes the resource from state
+	goneResp := fwresource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: updateResp.State}, &goneResp)
+	if goneResp.Diagnostics.HasError() {
+		t.Fatalf("Read after delete: %v", goneResp.Diagnostics)
+	}
+	if !goneResp.State.Raw.IsNull() {
+		t.Error("expected resource to be removed from state")
+	}
+}
+
+func TestMonitorResource_DeletedOutsideTerraform(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &MonitorResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	plan := testMonitorModel("deleted-in-ui")
+	createResp := fwresource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created MonitorResourceModel
+	createResp.State.Get(ctx, &created)
+	id, _ := strconv.Atoi(created.ID.ValueString())
+	server.RemoveMonitor(id)
+
+	// Delete treats the missing monitor as already deleted
+	deleteResp := fwresource.DeleteResponse{State: createResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: createResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Errorf("Delete: %v", deleteResp.Diagnostics)
+	}
+
+	// Read reports the drift by removing the resource from state
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.IsNull() {
+		t.Error("expected resource to be removed from state")
+	}
+}
+
+// testMonitorTags builds the tag blocks of a monitor model
+func testMonitorTags(t *testing.T, tags ...MonitorTagModel) types.Set {
+	t.Helper()
+
+	set, diags := types.SetValueFrom(context.Background(), types.ObjectType{AttrTypes: monitorTagAttrTypes}, tags)
+	if diags.HasError() {
+		t.Fatalf("building tag set: %v", diags)
+	}
+	return set
+}
+
+func TestMonitorResource_Tags(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	r := &MonitorResource{client: client}
+	s := testResourceSchema(t, r)
+
+	team := server.AddTag("team", "#2563EB")
+	prod := server.AddTag("env:prod", "#DC2626")
+
+	// Create with tags
+	plan := testMonitorModel("tagged")
+	plan.Tag = testMonitorTags(t,
+		MonitorTagModel{TagID: types.Int64Value(int64(team)), Value: types.StringValue("payments")},
+		MonitorTagModel{TagID: types.Int64Value(int64(prod)), Value: types.StringValue("")},
+	)
+	createResp := fwresource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created MonitorResourceModel
+	createResp.State.Get(ctx, &created)
+	id, _ := strconv.Atoi(created.ID.ValueString())
+
+	// Read reports the assigned tags
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+	var read MonitorResourceModel
+	readResp.State.Get(ctx, &read)
+	if !read.Tag.Equal(plan.Tag) {
+		t.Errorf("expected tags %v after read, got %v", plan.Tag, read.Tag)
+	}
+
+	// A value changed in the UI shows up as drift
+	if err := client.UpdateMonitorTag(ctx, team, id, "billing"); err != nil {
+		t.Fatalf("UpdateMonitorTag: %s", err)
+	}
+	driftResp := fwresource.ReadResponse{State: readResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: readResp.State}, &driftResp)
+	if driftResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", driftResp.Diagnostics)
+	}
+	var drifted MonitorResourceModel
+	driftResp.State.Get(ctx, &drifted)
+	wantDrift := testMonitorTags(t,
+		MonitorTagModel{TagID: types.Int64Value(int64(team)), Value: types.StringValue("billing")},
+		MonitorTagModel{TagID: types.Int64Value(int64(prod)), Value: types.StringValue("")},
+	)
+	if !drifted.Tag.Equal(wantDrift) {
+		t.Errorf("expected drifted tags %v, got %v", wantDrift, drifted.Tag)
+	}
+
+	// Update restores the configured value and drops the removed tag
+	plan = drifted
+	plan.Tag = testMonitorTags(t, MonitorTagModel{TagID: types.Int64Value(int64(team)), Value: types.StringValue("payments")})
+	updateResp := fwresource.UpdateResponse{State: driftResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: testPlan(t, s, &plan), State: driftResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+
+	monitor, err := client.GetMonitor(ctx, id)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if len(monitor.Tags) != 1 || monitor.Tags[0].TagID != team || monitor.Tags[0].Value != "payments" {
+		t.Errorf("expected only team=payments on server, got %+v", monitor.Tags)
+	}
+}
+
+func TestMonitorResource_Import(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	id := server.AddMonitor(map[string]interface{}{
+		"name":     "imported",
+		"type":     "http",
+		"url":      "https://imported.example.com",
+		"interval": 120,
+		"timeout":  48,
+		"active":   true,
+		"method":   "GET",
+	})
+
+	r := &MonitorResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	importResp := fwresource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: strconv.Itoa(id)}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("ImportState: %v", importResp.Diagnostics)
+	}
+
+	readResp := fwresource.ReadResponse{State: importResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: importResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	var imported MonitorResourceModel
+	readResp.State.Get(ctx, &imported)
+	if imported.Name.ValueString() != "imported" || imported.URL.ValueString() != "https://imported.example.com" {
+		t.Errorf("unexpected imported state: %+v", imported)
+	}
+	if imported.Interval.ValueInt64() != 120 || !imported.Active.ValueBool() {
+		t.Errorf("unexpected imported interval/active: %+v", imported)
+	}
+
+	invalidResp := fwresource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "not-a-number"}, &invalidResp)
+	if !invalidResp.Diagnostics.HasError() {
+		t.Error("expected error for non-numeric import ID")
+	}
+}
+
+func TestMonitorResource_ModifyPlanChecksServerVersion(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		version     string
+		monitorType string
+		timeout     types.Int64
+		parentID    types.Int64
+		wantErrors  []string
+	}{
+		{"supported type", "1.23.16", "real-browser", types.Int64Null(), types.Int64Null(), nil},
+		{"type from 2.0", "1.23.16", "snmp", types.Int64Null(), types.Int64Null(), []string{"Unsupported Monitor Type"}},
+		{"2.0 beta", "2.0.0-beta.2", "snmp", types.Int64Null(), types.Int64Null(), nil},
+		{"timeout on 1.22", "1.22.1", "http", types.Int64Value(10), types.Int64Null(), []string{"Unsupported Monitor Attribute"}},
+		{"default timeout on 1.22", "1.22.1", "http", types.Int64Null(), types.Int64Null(), nil},
+		{"parent on 1.22", "1.22.1", "http", types.Int64Null(), types.Int64Unknown(), []string{"Unsupported Monitor Attribute"}},
+		{"both on 1.22", "1.22.1", "group", types.Int64Value(10), types.Int64Null(), []string{"Unsupported Monitor Type", "Unsupported Monitor Attribute"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakekuma.NewServer(t, fakekuma.WithVersion(tt.version))
+			r := &MonitorResource{client: newTestClient(t, server)}
+			s := testResourceSchema(t, r)
+
+			config := testMonitorModel("versioned")
+			config.ID = types.StringNull()
+			config.Type = types.StringValue(tt.monitorType)
+			config.Timeout = tt.timeout
+			config.ParentID = tt.parentID
+			plan := config
+			plan.ID = types.StringUnknown()
+
+			req := fwresource.ModifyPlanRequest{
+				Config: testConfig(t, s, &config),
+				Plan:   testPlan(t, s, &plan),
+				State:  testEmptyState(s),
+			}
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &resp)
+
+			var got []string
+			for _, d := range resp.Diagnostics.Errors() {
+				got = append(got, d.Summary())
+			}
+			if len(got) != len(tt.wantErrors) {
+				t.Fatalf("expected errors %v, got %v", tt.wantErrors, resp.Diagnostics)
+			}
+			for i := range got {
+				if got[i] != tt.wantErrors[i] {
+					t.Errorf("expected errors %v, got %v", tt.wantErrors, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitorResource_DeleteGroupWarnsWithoutRefresh(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	groupID := server.AddMonitor(map[string]interface{}{"name": "service", "type": "group"})
+	server.AddMonitor(map[string]interface{}{"name": "api", "type": "http", "parent": groupID})
+
+	// With -refresh=false, or when only the group is destroyed, nothing has
+	// connected or read the monitor list before Delete
+	client, err := uptimekuma.NewClient(ctx, server.URL, server.Username, server.Password, uptimekuma.WithLazyConnect())
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+	r := &MonitorResource{client: client}
+	s := testResourceSchema(t, r)
+
+	group := testMonitorModel("service")
+	group.ID = types.StringValue(strconv.Itoa(groupID))
+	group.Type = types.StringValue("group")
+	state := testState(t, s, &group)
+
+	resp := fwresource.DeleteResponse{State: state}
+	r.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", resp.Diagnostics)
+	}
+	if warnings := resp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "Monitor Group Not Empty" {
+		t.Errorf("expected a warning about the child, got %v", resp.Diagnostics)
+	}
+}
+
+func TestMonitorResource_ModifyPlanWithoutConnection(t *testing.T) {
+	ctx := context.Background()
+
+	// A server that accepts connections but never answers, like one still
+	// starting behind a load balancer, is worse than a closed port: the
+	// connect only fails after the timeout
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	client, err := uptimekuma.NewClient(ctx, "http://"+listener.Addr().String(), "admin", "password",
+		uptimekuma.WithLazyConnect(), uptimekuma.WithTimeout(time.Second), uptimekuma.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+	r := &MonitorResource{client: client}
+	s := testResourceSchema(t, r)
+
+	// timeout is version-gated and set on almost every monitor, so the plan
+	// must not try to reach the server for it
+	config := testMonitorModel("unreachable")
+	config.ID = types.StringNull()
+	config.Timeout = types.Int64Value(10)
+	config.ParentID = types.Int64Value(1)
+	plan := config
+	plan.ID = types.StringUnknown()
+
+	req := fwresource.ModifyPlanRequest{
+		Config: testConfig(t, s, &config),
+		Plan:   testPlan(t, s, &plan),
+		State:  testEmptyState(s),
+	}
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	start := time.Now()
+	r.ModifyPlan(ctx, req, &resp)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("ModifyPlan took %s, expected it not to connect", elapsed)
+	}
+	if resp.Diagnostics.HasError() {
+		t.Errorf("expected the checks to be left to apply, got %v", resp.Diagnostics)
+	}
+	if client.Connected() {
+		t.Error("expected ModifyPlan not to connect")
+	}
+}
+
+func TestMonitorResource_CreateChecksServerVersion(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t, fakekuma.WithVersion("1.23.16"))
+	r := &MonitorResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	// A plan made while the server was unreachable skipped the check
+	config := testMonitorModel("snmp")
+	config.ID = types.StringNull()
+	config.Type = types.StringValue("snmp")
+	plan := config
+	plan.ID = types.StringUnknown()
+
+	resp := fwresource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, fwresource.CreateRequest{Config: testConfig(t, s, &config), Plan: testPlan(t, s, &plan)}, &resp)
+	if errs := resp.Diagnostics.Errors(); len(errs) != 1 || errs[0].Summary() != "Unsupported Monitor Type" {
+		t.Fatalf("expected unsupported type error, got %v", resp.Diagnostics)
+	}
+	if slices.Contains(server.Events(), "add") {
+		t.Errorf("expected no monitor to be sent to the server, got events %v", server.Events())
+	}
+}
+
+func TestMonitorResource_ModifyPlanChecksParent(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	outer := server.AddMonitor(map[string]interface{}{"name": "service", "type": "group"})
+	inner := server.AddMonitor(map[string]interface{}{"name": "api", "type": "group", "parent": outer})
+	leaf := server.AddMonitor(map[string]interface{}{"name": "health", "type": "http", "parent": inner})
+
+	client := newTestClient(t, server)
+	if err := client.RefreshMonitors(ctx); err != nil {
+		t.Fatalf("RefreshMonitors: %s", err)
+	}
+	r := &MonitorResource{client: client}
+	s := testResourceSchema(t, r)
+
+	tests := []struct {
+		name      string
+		id        int // 0 plans a new monitor
+		parentID  types.Int64
+		wantError bool
+	}{
+		{"no parent", outer, types.Int64Null(), false},
+		{"parent created in the same plan", 0, types.Int64Unknown(), false},
+		{"new monitor in group", 0, types.Int64Value(int64(inner)), false},
+		{"group into its own descendant", outer, types.Int64Value(int64(inner)), true},
+		{"monitor as its own parent", inner, types.Int64Value(int64(inner)), true},
+		{"parent is not a group", 0, types.Int64Value(int64(leaf)), true},
+		{"parent does not exist", 0, types.Int64Value(999), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testMonitorModel("nested")
+			config.ID = types.StringNull()
+			config.ParentID = tt.parentID
+			plan := config
+			plan.ID = types.StringUnknown()
+			state := testEmptyState(s)
+			if tt.id != 0 {
+				plan.ID = types.StringValue(strconv.Itoa(tt.id))
+				prior := plan
+				prior.ParentID = types.Int64Null()
+				state = testState(t, s, &prior)
+			}
+
+			req := fwresource.ModifyPlanRequest{
+				Config: testConfig(t, s, &config),
+				Plan:   testPlan(t, s, &plan),
+				State:  state,
+			}
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Fatalf("expected error %v, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantError && resp.Diagnostics.Errors()[0].Summary() != "Invalid Monitor Parent" {
+				t.Errorf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestMonitorResource_Groups(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &MonitorResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	create := func(plan MonitorResourceModel) (fwresource.CreateResponse, int) {
+		t.Helper()
+		resp := fwresource.CreateResponse{State: testEmptyState(s)}
+		r.Create(ctx, fwresource.CreateRequest{Plan: testPlan(t, s, &plan)}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Create: %v", resp.Diagnostics)
+		}
+		var created MonitorResourceModel
+		resp.State.Get(ctx, &created)
+		id, _ := strconv.Atoi(created.ID.ValueString())
+		return resp, id
+	}
+
+	groupPlan := testMonitorModel("service")
+	groupPlan.Type = types.StringValue("group")
+	groupPlan.URL = types.StringNull()
+	groupResp, groupID := create(groupPlan)
+
+	childPlan := testMonitorModel("api")
+	childPlan.ParentID = types.Int64Value(int64(groupID))
+	childResp, childID := create(childPlan)
+
+	if stored, _ := server.Monitor(childID); stored["parent"] != float64(groupID) {
+		t.Errorf("expected parent %d on server, got %v", groupID, stored["parent"])
+	}
+
+	// Read keeps the parent
+	readResp := fwresource.ReadResponse{State: childResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: childResp.State}, &readResp)
+	var read MonitorResourceModel
+	readResp.State.Get(ctx, &read)
+	if read.ParentID.ValueInt64() != int64(groupID) {
+		t.Errorf("expected parent_id %d after read, got %v", groupID, read.ParentID)
+	}
+
+	// Deleting a group that still has children warns and moves them to the
+	// top level
+	deleteResp := fwresource.DeleteResponse{State: groupResp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: groupResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+	}
+	if warnings := deleteResp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "Monitor Group Not Empty" {
+		t.Errorf("expected a warning about the child, got %v", deleteResp.Diagnostics)
+	}
+	if _, ok := server.Monitor(childID); !ok {
+		t.Fatal("expected child to survive deleting its group")
+	}
+
+	// The child shows the removed parent as drift
+	driftResp := fwresource.ReadResponse{State: readResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: readResp.State}, &driftResp)
+	var drifted MonitorResourceModel
+	driftResp.State.Get(ctx, &drifted)
+	if !drifted.ParentID.IsNull() {
+		t.Errorf("expected parent_id to be cleared, got %v", drifted.ParentID)
+	}
+}
@@ -2,9 +2,9 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -14,6 +14,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -26,7 +28,7 @@ func NewNotificationResource() resource.Resource {
 
 // NotificationResource defines the resource implementation.
 type NotificationResource struct {
-	client *Client
+	client *uptimekuma.Client
 }
 
 // NotificationResourceModel describes the resource data model.
@@ -102,12 +104,12 @@ func (r *NotificationResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(*uptimekuma.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *uptimekuma.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -117,6 +119,10 @@ func (r *NotificationResource) Configure(ctx context.Context, req resource.Confi
 }
 
 func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data NotificationResourceModel
 
 	// Read Terraform plan data into the model
@@ -127,12 +133,12 @@ func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Check if a notification with this name already exists
-	existingNotifications, err := r.client.GetNotifications()
+	existingNotifications, err := r.client.GetNotifications(ctx)
 	if err != nil {
 		resp.Diagnostics.AddWarning("Warning", fmt.Sprintf("Unable to check for existing notifications: %s", err))
 	}
 
-	var existingNotification *Notification
+	var existingNotification *uptimekuma.Notification
 	for i := range existingNotifications {
 		if existingNotifications[i].Name == data.Name.ValueString() {
 			existingNotification = &existingNotifications[i]
@@ -157,7 +163,7 @@ func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Create notification struct
-	notification := &Notification{
+	notification := &uptimekuma.Notification{
 		Name:          data.Name.ValueString(),
 		Type:          data.Type.ValueString(),
 		IsDefault:     data.IsDefault.ValueBool(),
@@ -165,18 +171,18 @@ func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRe
 		Config:        config,
 	}
 
-	var createdNotification *Notification
+	var createdNotification *uptimekuma.Notification
 	if existingNotification != nil {
 		// Adopt the existing notification and update it
 		notification.ID = existingNotification.ID
-		createdNotification, err = r.client.UpdateNotification(notification)
+		createdNotification, err = r.client.UpdateNotification(ctx, notification)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update existing notification, got error: %s", err))
 			return
 		}
 	} else {
 		// Create notification via API
-		createdNotification, err = r.client.CreateNotification(notification)
+		createdNotification, err = r.client.CreateNotification(ctx, notification)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create notification, got error: %s", err))
 			return
@@ -192,6 +198,10 @@ func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRe
 }
 
 func (r *NotificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data NotificationResourceModel
 
 	// Read Terraform prior state data into the model
@@ -209,10 +219,11 @@ func (r *NotificationResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	// Get notification from API
-	notification, err := r.client.GetNotification(id)
+	notification, err := r.client.GetNotification(ctx, id)
 	if err != nil {
 		// If the notification is not found, remove it from state (Terraform will recreate it)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, uptimekuma.ErrNotFound) {
+			tflog.Warn(ctx, fmt.Sprintf("Notification %d no longer exists, removing it from state", id))
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -267,6 +278,10 @@ func (r *NotificationResource) Read(ctx context.Context, req resource.ReadReques
 }
 
 func (r *NotificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data NotificationResourceModel
 
 	// Read Terraform plan data into the model
@@ -299,7 +314,7 @@ func (r *NotificationResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	// Create notification struct
-	notification := &Notification{
+	notification := &uptimekuma.Notification{
 		ID:            id,
 		Name:          data.Name.ValueString(),
 		Type:          data.Type.ValueString(),
@@ -309,7 +324,7 @@ func (r *NotificationResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	// Update notification via API
-	updatedNotification, err := r.client.UpdateNotification(notification)
+	updatedNotification, err := r.client.UpdateNotification(ctx, notification)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update notification, got error: %s", err))
 		return
@@ -323,6 +338,10 @@ func (r *NotificationResource) Update(ctx context.Context, req resource.UpdateRe
 }
 
 func (r *NotificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	var data NotificationResourceModel
 
 	// Read Terraform prior state data into the model
@@ -340,7 +359,11 @@ func (r *NotificationResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 
 	// Delete notification via API
-	err = r.client.DeleteNotification(id)
+	err = r.client.DeleteNotification(ctx, id)
+	if errors.Is(err, uptimekuma.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Notification %d was already deleted", id))
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete notification, got error: %s", err))
 		return
@@ -348,6 +371,10 @@ func (r *NotificationResource) Delete(ctx context.Context, req resource.DeleteRe
 }
 
 func (r *NotificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
 	// Import by notification ID
 	id, err := strconv.Atoi(req.ID)
 	if err != nil {
@@ -356,7 +383,7 @@ func (r *NotificationResource) ImportState(ctx context.Context, req resource.Imp
 	}
 
 	// Verify notification exists
-	_, err = r.client.GetNotification(id)
+	_, err = r.client.GetNotification(ctx, id)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find notification with ID %d: %s", id, err))
 		return
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestNotificationResource_CRUD(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &NotificationResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	config, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{
+		"webhookURL": "https://hooks.example.com/1",
+	})
+	plan := NotificationResourceModel{
+		ID:            types.StringUnknown(),
+		Name:          types.StringValue("webhook"),
+		Type:          types.StringValue("webhook"),
+		IsDefault:     types.BoolValue(false),
+		ApplyExisting: types.BoolValue(false),
+		Active:        types.BoolUnknown(),
+		Config:        config,
+	}
+
+	// Create
+	createResp := resource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, resource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created NotificationResourceModel
+	createResp.State.Get(ctx, &created)
+	id, err := strconv.Atoi(created.ID.ValueString())
+	if err != nil {
+		t.Fatalf("unexpected notification ID %q", created.ID.ValueString())
+	}
+	if _, ok := server.Notification(id); !ok {
+		t.Fatalf("notification %d not stored on server", id)
+	}
+
+	// Read
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	var read NotificationResourceModel
+	readResp.State.Get(ctx, &read)
+	var readConfig map[string]string
+	read.Config.ElementsAs(ctx, &readConfig, false)
+	if read.Type.ValueString() != "webhook" || readConfig["webhookURL"] != "https://hooks.example.com/1" {
+		t.Errorf("unexpected state after read: %+v", read)
+	}
+
+	// Update
+	plan = read
+	plan.Name = types.StringValue("webhook-renamed")
+	updateResp := resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: testPlan(t, s, &plan), State: readResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+
+	// Import
+	importResp := resource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: strconv.Itoa(id)}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("ImportState: %v", importResp.Diagnostics)
+	}
+
+	// Delete
+	deleteResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+	}
+
+	// Read after delete removes the resource from state
+	goneResp := resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &goneResp)
+	if goneResp.Diagnostics.HasError() {
+		t.Fatalf("Read after delete: %v", goneResp.Diagnostics)
+	}
+	if !goneResp.State.Raw.IsNull() {
+		t.Error("expected resource to be removed from state")
+	}
+
+	// Deleting a notification that is already gone succeeds
+	againResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &againResp)
+	if againResp.Diagnostics.HasError() {
+		t.Errorf("Delete of deleted notification: %v", againResp.Diagnostics)
+	}
+}
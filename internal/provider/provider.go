@@ -2,15 +2,24 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	neturl "net/url"
 	"os"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 // Ensure UptimeKumaProvider satisfies various provider interfaces.
@@ -26,9 +35,30 @@ type UptimeKumaProvider struct {
 
 // UptimeKumaProviderModel describes the provider data model.
 type UptimeKumaProviderModel struct {
-	URL      types.String `tfsdk:"url"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	URL        types.String `tfsdk:"url"`
+	Username   types.String `tfsdk:"username"`
+	Password   types.String `tfsdk:"password"`
+	Timeout    types.Int64  `tfsdk:"timeout"`
+	SocketPath types.String `tfsdk:"socket_path"`
+
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryBackoff types.String `tfsdk:"retry_backoff"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	TLSServerName      types.String `tfsdk:"tls_server_name"`
+
+	Headers  types.Map    `tfsdk:"headers"`
+	ProxyURL types.String `tfsdk:"proxy_url"`
+
+	TOTPSecret types.String `tfsdk:"totp_secret"`
+	TOTPCode   types.String `tfsdk:"totp_code"`
+
+	Token          types.String `tfsdk:"token"`
+	TokenCacheFile types.String `tfsdk:"token_cache_file"`
 }
 
 func (p *UptimeKumaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -40,7 +70,7 @@ func (p *UptimeKumaProvider) Schema(ctx context.Context, req provider.SchemaRequ
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
-				MarkdownDescription: "The URL of the Uptime Kuma instance",
+				MarkdownDescription: "The URL of the Uptime Kuma instance. A path, such as `https://ops.example.com/uptime/`, is kept for instances served behind a reverse proxy.",
 				Optional:            true,
 			},
 			"username": schema.StringAttribute{
@@ -52,6 +82,76 @@ func (p *UptimeKumaProvider) Schema(ctx context.Context, req provider.SchemaRequ
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Seconds to wait for Uptime Kuma to answer each attempt of a request. Defaults to 30.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "How often to repeat a request that Uptime Kuma rate limited, or a read or idempotent write whose answer got lost. Defaults to 3; 0 disables retries.",
+				Optional:            true,
+			},
+			"retry_backoff": schema.StringAttribute{
+				MarkdownDescription: "Delay before the first retry, as a duration such as `500ms` or `2s`. It doubles with every further retry, up to 30s, with random jitter. Defaults to `500ms`.",
+				Optional:            true,
+			},
+			"socket_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the Socket.IO endpoint on the Uptime Kuma host. Defaults to the path of `url` followed by `/socket.io/`.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificates to trust instead of the system roots. Conflicts with `ca_cert_file`.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file of PEM-encoded CA certificates to trust instead of the system roots. Conflicts with `ca_cert_pem`.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate for mutual TLS. Requires `client_key`.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of `client_cert`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip verification of the server certificate. Only meant for testing.",
+				Optional:            true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: "Server name used to verify the certificate, when it differs from the host in `url`.",
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Extra headers sent with the WebSocket upgrade and HTTP requests, e.g. `CF-Access-Client-Id` for Cloudflare Access.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of an `http` or `socks5` proxy to connect through. SOCKS5 proxies resolve the host name of the instance themselves. Defaults to the `HTTPS_PROXY`/`HTTP_PROXY` environment variables.",
+				Optional:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "Session token (JWT) to authenticate with instead of a password login. May also be set with the `UPTIMEKUMA_TOKEN` environment variable. When set, `username` and `password` are optional and only used if the token is rejected.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"token_cache_file": schema.StringAttribute{
+				MarkdownDescription: "File to keep session tokens in between runs, so the password is only sent when the cached token has expired.",
+				Optional:            true,
+			},
+			"totp_secret": schema.StringAttribute{
+				MarkdownDescription: "Base32 secret of the account's two-factor authentication, used to generate login codes. Conflicts with `totp_code`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"totp_code": schema.StringAttribute{
+				MarkdownDescription: "Current two-factor code for accounts with 2FA enabled. Conflicts with `totp_secret`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
 		},
 	}
 }
@@ -68,37 +168,13 @@ func (p *UptimeKumaProvider) Configure(ctx context.Context, req provider.Configu
 	// Configuration values are now available:
 	// data.URL, data.Username, data.Password
 
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value.
-
-	if data.URL.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("url"),
-			"Unknown Uptime Kuma URL",
-			"The provider cannot create the Uptime Kuma API client as there is an unknown configuration value for the Uptime Kuma URL. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the UPTIMEKUMA_URL environment variable.",
-		)
-	}
-
-	if data.Username.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Unknown Uptime Kuma Username",
-			"The provider cannot create the Uptime Kuma API client as there is an unknown configuration value for the Uptime Kuma username. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the UPTIMEKUMA_USERNAME environment variable.",
-		)
-	}
-
-	if data.Password.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Unknown Uptime Kuma Password",
-			"The provider cannot create the Uptime Kuma API client as there is an unknown configuration value for the Uptime Kuma password. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the UPTIMEKUMA_PASSWORD environment variable.",
-		)
-	}
-
-	if resp.Diagnostics.HasError() {
+	// The URL, credentials and other connection settings may come from
+	// resources created in the same apply, such as an Uptime Kuma container
+	// and its certificates. Until they are all known there is no client;
+	// resources can still be planned, and fail if they need the server
+	// before apply.
+	if data.hasUnknownValues() {
+		tflog.Warn(ctx, "Uptime Kuma connection settings are not known yet, skipping client creation")
 		return
 	}
 
@@ -108,6 +184,7 @@ func (p *UptimeKumaProvider) Configure(ctx context.Context, req provider.Configu
 	url := os.Getenv("UPTIMEKUMA_URL")
 	username := os.Getenv("UPTIMEKUMA_USERNAME")
 	password := os.Getenv("UPTIMEKUMA_PASSWORD")
+	token := os.Getenv("UPTIMEKUMA_TOKEN")
 
 	if !data.URL.IsNull() {
 		url = data.URL.ValueString()
@@ -121,6 +198,10 @@ func (p *UptimeKumaProvider) Configure(ctx context.Context, req provider.Configu
 		password = data.Password.ValueString()
 	}
 
+	if !data.Token.IsNull() {
+		token = data.Token.ValueString()
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -134,38 +215,177 @@ func (p *UptimeKumaProvider) Configure(ctx context.Context, req provider.Configu
 		)
 	}
 
-	if username == "" {
+	// A session token replaces the username and password
+	if username == "" && token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
 			"Missing Uptime Kuma Username",
 			"The provider requires a username for Uptime Kuma authentication. "+
-				"Set the username value in the configuration or use the UPTIMEKUMA_USERNAME environment variable. "+
+				"Set the username value in the configuration or use the UPTIMEKUMA_USERNAME environment variable, "+
+				"or authenticate with a token instead. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if password == "" {
+	if password == "" && token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Missing Uptime Kuma Password",
 			"The provider requires a password for Uptime Kuma authentication. "+
-				"Set the password value in the configuration or use the UPTIMEKUMA_PASSWORD environment variable. "+
+				"Set the password value in the configuration or use the UPTIMEKUMA_PASSWORD environment variable, "+
+				"or authenticate with a token instead. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	timeout := uptimekuma.DefaultTimeout
+	if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() {
+		if data.Timeout.ValueInt64() <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Uptime Kuma Timeout",
+				"The timeout must be a positive number of seconds.",
+			)
+		}
+		timeout = time.Duration(data.Timeout.ValueInt64()) * time.Second
+	}
+
+	maxRetries := uptimekuma.DefaultMaxRetries
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		if data.MaxRetries.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Uptime Kuma Max Retries",
+				"The number of retries must not be negative.",
+			)
+		}
+		maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	retryBackoff := uptimekuma.DefaultRetryBackoff
+	if !data.RetryBackoff.IsNull() && !data.RetryBackoff.IsUnknown() {
+		backoff, err := time.ParseDuration(data.RetryBackoff.ValueString())
+		if err != nil || backoff < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_backoff"),
+				"Invalid Uptime Kuma Retry Backoff",
+				"The retry backoff must be a non-negative duration such as 500ms or 2s.",
+			)
+		}
+		retryBackoff = backoff
+	}
+
+	if !data.CACertPEM.IsNull() && !data.CACertFile.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_file"),
+			"Conflicting Uptime Kuma CA Certificates",
+			"Set either ca_cert_pem or ca_cert_file, not both.",
+		)
+	}
+
+	if !data.TOTPSecret.IsNull() && !data.TOTPCode.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("totp_code"),
+			"Conflicting Uptime Kuma Two-Factor Settings",
+			"Set either totp_secret or totp_code, not both.",
+		)
+	}
+
+	if data.ClientCert.IsNull() != data.ClientKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Incomplete Uptime Kuma Client Certificate",
+			"Mutual TLS requires both client_cert and client_key.",
+		)
+	}
+
+	var proxyURL *neturl.URL
+	if !data.ProxyURL.IsNull() && !data.ProxyURL.IsUnknown() {
+		parsed, err := neturl.Parse(data.ProxyURL.ValueString())
+		if err != nil || parsed.Host == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Uptime Kuma Proxy URL",
+				fmt.Sprintf("The proxy_url %q is not a valid URL.", data.ProxyURL.ValueString()),
+			)
+		} else {
+			// The WebSocket dialer only speaks plain HTTP CONNECT and SOCKS5
+			switch parsed.Scheme {
+			case "http", "socks5":
+				proxyURL = parsed
+			default:
+				resp.Diagnostics.AddAttributeError(
+					path.Root("proxy_url"),
+					"Invalid Uptime Kuma Proxy URL",
+					fmt.Sprintf("Unsupported proxy scheme %q; use http or socks5.", parsed.Scheme),
+				)
+			}
+		}
+	}
+
+	var headers http.Header
+	if !data.Headers.IsNull() && !data.Headers.IsUnknown() {
+		var values map[string]string
+		resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &values, false)...)
+		headers = make(http.Header, len(values))
+		for name, value := range values {
+			headers.Set(name, value)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	tlsConfig, err := buildTLSConfig(data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Uptime Kuma TLS Configuration",
+			"The provider cannot create the Uptime Kuma API client because the TLS settings are invalid: "+err.Error(),
+		)
+		return
+	}
+
 	ctx = tflog.SetField(ctx, "uptimekuma_url", url)
 	ctx = tflog.SetField(ctx, "uptimekuma_username", username)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "uptimekuma_password")
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "uptimekuma_password", "uptimekuma_token")
 
 	tflog.Debug(ctx, "Creating Uptime Kuma client")
 
 	// Create a new Uptime Kuma client using the configuration values
-	client, err := NewClient(url, username, password)
+	// Connecting is left to the first resource or data source that needs
+	// the server, so validate and plan work before it is reachable
+	opts := []uptimekuma.ClientOption{
+		uptimekuma.WithLazyConnect(),
+		uptimekuma.WithTimeout(timeout),
+		uptimekuma.WithRetry(maxRetries, retryBackoff),
+	}
+	if !data.SocketPath.IsNull() && !data.SocketPath.IsUnknown() {
+		opts = append(opts, uptimekuma.WithSocketPath(data.SocketPath.ValueString()))
+	}
+	if tlsConfig != nil {
+		opts = append(opts, uptimekuma.WithTLSConfig(tlsConfig))
+	}
+	if len(headers) > 0 {
+		opts = append(opts, uptimekuma.WithHeaders(headers))
+	}
+	if proxyURL != nil {
+		opts = append(opts, uptimekuma.WithProxyURL(proxyURL))
+	}
+	if token != "" {
+		opts = append(opts, uptimekuma.WithToken(token))
+	}
+	if !data.TokenCacheFile.IsNull() && !data.TokenCacheFile.IsUnknown() {
+		opts = append(opts, uptimekuma.WithTokenCache(data.TokenCacheFile.ValueString()))
+	}
+	if !data.TOTPSecret.IsNull() {
+		opts = append(opts, uptimekuma.WithTOTPSecret(data.TOTPSecret.ValueString()))
+	}
+	if !data.TOTPCode.IsNull() {
+		opts = append(opts, uptimekuma.WithTOTPCode(data.TOTPCode.ValueString()))
+	}
+
+	client, err := uptimekuma.NewClient(ctx, url, username, password, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Uptime Kuma API Client",
@@ -184,10 +404,46 @@ func (p *UptimeKumaProvider) Configure(ctx context.Context, req provider.Configu
 	tflog.Info(ctx, "Configured Uptime Kuma client", map[string]any{"success": true})
 }
 
+// hasUnknownValues reports whether any setting the client is built from is
+// not known yet
+func (m UptimeKumaProviderModel) hasUnknownValues() bool {
+	for _, value := range []attr.Value{
+		m.URL, m.Username, m.Password, m.Timeout, m.SocketPath,
+		m.MaxRetries, m.RetryBackoff,
+		m.CACertPEM, m.CACertFile, m.ClientCert, m.ClientKey, m.InsecureSkipVerify, m.TLSServerName,
+		m.Headers, m.ProxyURL,
+		m.TOTPSecret, m.TOTPCode,
+		m.Token, m.TokenCacheFile,
+	} {
+		if value.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
+
+// clientConfigured reports whether the provider passed a client, adding an
+// error if it could not because its configuration is not known yet
+func clientConfigured(client *uptimekuma.Client, diags *diag.Diagnostics) bool {
+	if client != nil {
+		return true
+	}
+
+	diags.AddError(
+		"Uptime Kuma Client Not Configured",
+		"The provider configuration depends on values that are not known yet, such as the URL, credentials or certificates of an Uptime Kuma instance "+
+			"created in the same apply. New resources can be planned, but existing ones cannot be read or changed until those values are known. "+
+			"Apply the resources the provider configuration depends on first, e.g. with -target.",
+	)
+	return false
+}
+
 func (p *UptimeKumaProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewMonitorResource,
 		NewNotificationResource,
+		NewTagResource,
+		NewStatusPageResource,
 	}
 }
 
@@ -204,3 +460,49 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// buildTLSConfig turns the TLS attributes into a tls.Config. It returns nil
+// when none are set so the client keeps Go's defaults.
+func buildTLSConfig(data UptimeKumaProviderModel) (*tls.Config, error) {
+	caPEM := data.CACertPEM.ValueString()
+	if file := data.CACertFile.ValueString(); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca_cert_file: %w", err)
+		}
+		caPEM = string(content)
+	}
+
+	certPEM := data.ClientCert.ValueString()
+	keyPEM := data.ClientKey.ValueString()
+	serverName := data.TLSServerName.ValueString()
+	skipVerify := data.InsecureSkipVerify.ValueBool()
+
+	if caPEM == "" && certPEM == "" && serverName == "" && !skipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: skipVerify,
+	}
+
+	if caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, fmt.Errorf("no PEM certificates found in the CA certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	if certPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"uptimekuma": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+func testAccPreCheck(t *testing.T) {
+	// Acceptance tests run against an in-process fake Uptime Kuma server,
+	// so there is no external instance or credentials to verify.
+}
+
+// testAccProviderConfig returns the provider block pointing at the fake server
+func testAccProviderConfig(server *fakekuma.Server) string {
+	return `
+provider "uptimekuma" {
+  url      = "` + server.URL + `"
+  username = "` + server.Username + `"
+  password = "` + server.Password + `"
+}
+`
+}
+
+func TestProviderConfigure_ConnectsOnFirstUse(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t, fakekuma.WithTOTP("JBSWY3DPEHPK3PXP"))
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	configure := func(url string) *uptimekuma.Client {
+		config := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		config.SetAttribute(ctx, path.Root("url"), url)
+		config.SetAttribute(ctx, path.Root("username"), server.Username)
+		config.SetAttribute(ctx, path.Root("password"), server.Password)
+
+		resp := &provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Configure: %v", resp.Diagnostics)
+		}
+
+		client := resp.ResourceData.(*uptimekuma.Client)
+		t.Cleanup(client.Close)
+		return client
+	}
+
+	// A server that does not exist yet is only a problem once it is needed
+	unreachable := fakekuma.NewServer(t)
+	unreachable.Close()
+	if err := configure(unreachable.URL).RefreshMonitors(ctx); err == nil {
+		t.Error("expected an error when using a client for an unreachable server")
+	}
+
+	// Nothing is sent before the first use, which then fails on the missing 2FA code
+	client := configure(server.URL)
+	if events := server.Events(); len(events) != 0 {
+		t.Errorf("expected no events before first use, got %v", events)
+	}
+	if err := client.RefreshMonitors(ctx); !errors.Is(err, uptimekuma.ErrTOTPRequired) {
+		t.Errorf("expected ErrTOTPRequired on first use, got: %v", err)
+	}
+}
+
+func TestProviderConfigure_UnknownCredentials(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	// The URL of an instance created in the same apply is unknown at plan time
+	config := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	config.SetAttribute(ctx, path.Root("url"), types.StringUnknown())
+	config.SetAttribute(ctx, path.Root("username"), "admin")
+	config.SetAttribute(ctx, path.Root("password"), types.StringUnknown())
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", resp.Diagnostics)
+	}
+	if resp.ResourceData != nil {
+		t.Fatalf("expected no client, got %v", resp.ResourceData)
+	}
+
+	// New monitors can be planned without a client
+	r := &MonitorResource{}
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: resp.ResourceData}, &resource.ConfigureResponse{})
+	monitorSchema := testResourceSchema(t, r)
+	monitor := testMonitorModel("snmp")
+	monitor.Type = types.StringValue("snmp")
+	planResp := resource.ModifyPlanResponse{Plan: testPlan(t, monitorSchema, &monitor)}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+		Config: testConfig(t, monitorSchema, &monitor),
+		Plan:   planResp.Plan,
+		State:  testEmptyState(monitorSchema),
+	}, &planResp)
+	if planResp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan: %v", planResp.Diagnostics)
+	}
+
+	// but using the server fails instead of panicking
+	createResp := resource.CreateResponse{State: testEmptyState(monitorSchema)}
+	r.Create(ctx, resource.CreateRequest{Plan: planResp.Plan}, &createResp)
+	if errs := createResp.Diagnostics.Errors(); len(errs) != 1 || errs[0].Summary() != "Uptime Kuma Client Not Configured" {
+		t.Errorf("unexpected diagnostics: %v", createResp.Diagnostics)
+	}
+}
+
+func TestProviderConfigure_UnknownConnectionSettings(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	// Settings read as "" or false while unknown would build a client with
+	// the wrong TLS, proxy or login settings
+	for attribute, unknown := range map[string]attr.Value{
+		"ca_cert_pem":      types.StringUnknown(),
+		"ca_cert_file":     types.StringUnknown(),
+		"client_cert":      types.StringUnknown(),
+		"client_key":       types.StringUnknown(),
+		"tls_server_name":  types.StringUnknown(),
+		"headers":          types.MapUnknown(types.StringType),
+		"proxy_url":        types.StringUnknown(),
+		"socket_path":      types.StringUnknown(),
+		"token_cache_file": types.StringUnknown(),
+		"totp_secret":      types.StringUnknown(),
+		"totp_code":        types.StringUnknown(),
+	} {
+		config := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		config.SetAttribute(ctx, path.Root("url"), server.URL)
+		config.SetAttribute(ctx, path.Root("username"), server.Username)
+		config.SetAttribute(ctx, path.Root("password"), server.Password)
+		config.SetAttribute(ctx, path.Root(attribute), unknown)
+
+		resp := &provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("%s: unexpected diagnostics: %v", attribute, resp.Diagnostics)
+		}
+		if resp.ResourceData != nil {
+			t.Errorf("%s: expected no client while the value is unknown", attribute)
+		}
+	}
+}
+
+func TestProviderConfigure_InvalidRetrySettings(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	config := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	config.SetAttribute(ctx, path.Root("url"), server.URL)
+	config.SetAttribute(ctx, path.Root("username"), server.Username)
+	config.SetAttribute(ctx, path.Root("password"), server.Password)
+	config.SetAttribute(ctx, path.Root("max_retries"), int64(-1))
+	config.SetAttribute(ctx, path.Root("retry_backoff"), "soon")
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, resp)
+
+	var summaries []string
+	for _, d := range resp.Diagnostics.Errors() {
+		summaries = append(summaries, d.Summary())
+	}
+	if len(summaries) != 2 || summaries[0] != "Invalid Uptime Kuma Max Retries" || summaries[1] != "Invalid Uptime Kuma Retry Backoff" {
+		t.Errorf("unexpected diagnostics: %v", summaries)
+	}
+}
+
+func TestProviderConfigure_ProxySchemes(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	p := New("test")()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	s := schemaResp.Schema
+
+	for proxyURL, valid := range map[string]bool{
+		"http://proxy.example.com:3128":    true,
+		"socks5://proxy.example.com:1080":  true,
+		"https://proxy.example.com:3128":   false,
+		"socks5h://proxy.example.com:1080": false,
+		"ftp://proxy.example.com":          false,
+	} {
+		config := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		config.SetAttribute(ctx, path.Root("url"), server.URL)
+		config.SetAttribute(ctx, path.Root("username"), server.Username)
+		config.SetAttribute(ctx, path.Root("password"), server.Password)
+		config.SetAttribute(ctx, path.Root("proxy_url"), proxyURL)
+
+		resp := &provider.ConfigureResponse{}
+		p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, resp)
+
+		if valid && resp.Diagnostics.HasError() {
+			t.Errorf("%s: unexpected diagnostics: %v", proxyURL, resp.Diagnostics)
+		}
+		if !valid && (len(resp.Diagnostics.Errors()) != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Uptime Kuma Proxy URL") {
+			t.Errorf("%s: expected invalid proxy URL, got: %v", proxyURL, resp.Diagnostics)
+		}
+	}
+}
+
+// newTestClient connects a Client to the fake server and closes it when the test finishes
+func newTestClient(t *testing.T, server *fakekuma.Server) *uptimekuma.Client {
+	t.Helper()
+
+	client, err := uptimekuma.NewClient(context.Background(), server.URL, server.Username, server.Password)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// testResourceSchema returns the schema of a resource
+func testResourceSchema(t *testing.T, r resource.Resource) schema.Schema {
+	t.Helper()
+
+	resp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Schema: %v", resp.Diagnostics)
+	}
+
+	return resp.Schema
+}
+
+// testPlan builds a plan holding the given resource model
+func testPlan(t *testing.T, s schema.Schema, model interface{}) tfsdk.Plan {
+	t.Helper()
+
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if diags := plan.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("Plan.Set: %v", diags)
+	}
+
+	return plan
+}
+
+// testConfig builds a configuration holding the given resource model
+func testConfig(t *testing.T, s schema.Schema, model interface{}) tfsdk.Config {
+	t.Helper()
+
+	return tfsdk.Config{Schema: s, Raw: testPlan(t, s, model).Raw}
+}
+
+// testState builds a state holding the given resource model
+func testState(t *testing.T, s schema.Schema, model interface{}) tfsdk.State {
+	t.Helper()
+
+	state := testEmptyState(s)
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("State.Set: %v", diags)
+	}
+
+	return state
+}
+
+// testEmptyState builds a null state, as seen by Create and ImportState
+func testEmptyState(s schema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithTLS())
+
+	tests := []struct {
+		name    string
+		model   UptimeKumaProviderModel
+		wantErr bool
+	}{
+		{"system roots", UptimeKumaProviderModel{}, true},
+		{"custom CA", UptimeKumaProviderModel{CACertPEM: types.StringValue(server.CACertPEM())}, false},
+		{"server name", UptimeKumaProviderModel{CACertPEM: types.StringValue(server.CACertPEM()), TLSServerName: types.StringValue("example.com")}, false},
+		{"wrong server name", UptimeKumaProviderModel{CACertPEM: types.StringValue(server.CACertPEM()), TLSServerName: types.StringValue("kuma.internal")}, true},
+		{"skip verify", UptimeKumaProviderModel{InsecureSkipVerify: types.BoolValue(true)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := buildTLSConfig(tt.model)
+			if err != nil {
+				t.Fatalf("buildTLSConfig: %s", err)
+			}
+
+			var opts []uptimekuma.ClientOption
+			if tlsConfig != nil {
+				opts = append(opts, uptimekuma.WithTLSConfig(tlsConfig))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := uptimekuma.NewClient(ctx, server.URL, server.Username, server.Password, opts...)
+			if tt.wantErr {
+				if err == nil {
+					client.Close()
+					t.Fatal("expected TLS error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClient: %s", err)
+			}
+			defer client.Close()
+
+			// The HTTP client shares the TLS settings
+			resp, err := client.HTTPClient.Get(server.URL)
+			if err != nil {
+				t.Fatalf("HTTPClient: %s", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestBuildTLSConfig_ClientCertificate(t *testing.T) {
+	pool, certPEM, keyPEM := testClientCertificate(t)
+	server := fakekuma.NewServer(t, fakekuma.WithClientCAs(pool))
+	ctx := context.Background()
+
+	model := UptimeKumaProviderModel{CACertPEM: types.StringValue(server.CACertPEM())}
+	tlsConfig, err := buildTLSConfig(model)
+	if err != nil {
+		t.Fatalf("buildTLSConfig: %s", err)
+	}
+	if client, err := uptimekuma.NewClient(ctx, server.URL, server.Username, server.Password, uptimekuma.WithTLSConfig(tlsConfig)); err == nil {
+		client.Close()
+		t.Fatal("expected connection without client certificate to fail")
+	}
+
+	model.ClientCert = types.StringValue(certPEM)
+	model.ClientKey = types.StringValue(keyPEM)
+	tlsConfig, err = buildTLSConfig(model)
+	if err != nil {
+		t.Fatalf("buildTLSConfig: %s", err)
+	}
+	client, err := uptimekuma.NewClient(ctx, server.URL, server.Username, server.Password, uptimekuma.WithTLSConfig(tlsConfig))
+	if err != nil {
+		t.Fatalf("NewClient with client certificate: %s", err)
+	}
+	client.Close()
+}
+
+// testClientCertificate creates a self-signed client certificate and returns
+// a pool trusting it along with the PEM-encoded certificate and key
+func testClientCertificate(t *testing.T) (*x509.CertPool, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %s", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return pool, string(certPEM), string(keyPEM)
+}
@@ -0,0 +1,503 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StatusPageResource{}
+var _ resource.ResourceWithImportState = &StatusPageResource{}
+
+func NewStatusPageResource() resource.Resource {
+	return &StatusPageResource{}
+}
+
+// StatusPageResource defines the resource implementation.
+type StatusPageResource struct {
+	client *uptimekuma.Client
+}
+
+// StatusPageResourceModel describes the resource data model.
+type StatusPageResourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Slug                  types.String `tfsdk:"slug"`
+	Title                 types.String `tfsdk:"title"`
+	Description           types.String `tfsdk:"description"`
+	Theme                 types.String `tfsdk:"theme"`
+	FooterText            types.String `tfsdk:"footer_text"`
+	CustomCSS             types.String `tfsdk:"custom_css"`
+	ShowTags              types.Bool   `tfsdk:"show_tags"`
+	ShowPoweredBy         types.Bool   `tfsdk:"show_powered_by"`
+	ShowCertificateExpiry types.Bool   `tfsdk:"show_certificate_expiry"`
+	GoogleAnalyticsID     types.String `tfsdk:"google_analytics_id"`
+	Icon                  types.String `tfsdk:"icon"`
+	PublicGroup           types.List   `tfsdk:"public_group"`
+}
+
+// StatusPagePublicGroupModel describes a public_group block of a status page.
+type StatusPagePublicGroupModel struct {
+	Name    types.String `tfsdk:"name"`
+	Monitor types.List   `tfsdk:"monitor"`
+}
+
+// StatusPagePublicMonitorModel describes a monitor block of a public group.
+type StatusPagePublicMonitorModel struct {
+	ID      types.Int64 `tfsdk:"id"`
+	SendURL types.Bool  `tfsdk:"send_url"`
+}
+
+// statusPagePublicMonitorAttrTypes are the attribute types of a monitor block
+var statusPagePublicMonitorAttrTypes = map[string]attr.Type{
+	"id":       types.Int64Type,
+	"send_url": types.BoolType,
+}
+
+// statusPagePublicGroupAttrTypes are the attribute types of a public_group
+// block
+var statusPagePublicGroupAttrTypes = map[string]attr.Type{
+	"name":    types.StringType,
+	"monitor": types.ListType{ElemType: types.ObjectType{AttrTypes: statusPagePublicMonitorAttrTypes}},
+}
+
+func (r *StatusPageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_status_page"
+}
+
+func (r *StatusPageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Uptime Kuma status page",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Status page identifier, which is its slug",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"slug": schema.StringAttribute{
+				MarkdownDescription: "Address of the page, which is served at /status/<slug>",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Page title",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description shown below the title",
+				Optional:            true,
+			},
+			"theme": schema.StringAttribute{
+				MarkdownDescription: "Colour theme: auto, light or dark",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("auto"),
+			},
+			"footer_text": schema.StringAttribute{
+				MarkdownDescription: "Text shown in the page footer",
+				Optional:            true,
+			},
+			"custom_css": schema.StringAttribute{
+				MarkdownDescription: "CSS added to the page",
+				Optional:            true,
+			},
+			"show_tags": schema.BoolAttribute{
+				MarkdownDescription: "Show the tags of the monitors",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"show_powered_by": schema.BoolAttribute{
+				MarkdownDescription: "Show the \"Powered by Uptime Kuma\" footer",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"show_certificate_expiry": schema.BoolAttribute{
+				MarkdownDescription: "Show when the certificates of HTTPS monitors expire",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"google_analytics_id": schema.StringAttribute{
+				MarkdownDescription: "Google Analytics tag ID to add to the page",
+				Optional:            true,
+			},
+			"icon": schema.StringAttribute{
+				MarkdownDescription: "Icon URL, or a PNG data URL (data:image/png;base64,...) to upload. Defaults to the Uptime Kuma logo",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"public_group": schema.ListNestedBlock{
+				MarkdownDescription: "Group of monitors shown on the page, in display order. Groups not configured here are removed from the page.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Group name (e.g., Services)",
+							Required:            true,
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"monitor": schema.ListNestedBlock{
+							MarkdownDescription: "Monitor shown in the group, in display order",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.Int64Attribute{
+										MarkdownDescription: "Monitor ID",
+										Required:            true,
+									},
+									"send_url": schema.BoolAttribute{
+										MarkdownDescription: "Show the monitored URL as a link",
+										Optional:            true,
+										Computed:            true,
+										Default:             booldefault.StaticBool(false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *StatusPageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*uptimekuma.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *uptimekuma.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *StatusPageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	var data StatusPageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groups, diags := publicGroupsFromModel(ctx, data.PublicGroup)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create status page via API
+	page, err := r.client.CreateStatusPage(ctx, statusPageFromModel(&data), groups)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create status page, got error: %s", err))
+		return
+	}
+
+	data.ID = data.Slug
+	if data.Icon.IsUnknown() {
+		data.Icon = types.StringValue(page.Icon)
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	var data StatusPageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	slug := data.ID.ValueString()
+
+	// Get status page from API
+	page, err := r.client.GetStatusPage(ctx, slug)
+	if err != nil {
+		// If the status page is not found, remove it from state (Terraform will recreate it)
+		if errors.Is(err, uptimekuma.ErrNotFound) {
+			tflog.Warn(ctx, fmt.Sprintf("Status page %q no longer exists, removing it from state", slug))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status page, got error: %s", err))
+		return
+	}
+
+	// Update the data model with values from API, keeping optional
+	// strings that were never set null
+	data.Slug = types.StringValue(page.Slug)
+	data.Title = types.StringValue(page.Title)
+	data.Theme = types.StringValue(page.Theme)
+	data.ShowTags = types.BoolValue(page.ShowTags)
+	data.ShowPoweredBy = types.BoolValue(page.ShowPoweredBy)
+	data.ShowCertificateExpiry = types.BoolValue(page.ShowCertificateExpiry)
+
+	if page.Description != "" || !data.Description.IsNull() {
+		data.Description = types.StringValue(page.Description)
+	}
+	if page.FooterText != "" || !data.FooterText.IsNull() {
+		data.FooterText = types.StringValue(page.FooterText)
+	}
+	if page.CustomCSS != "" || !data.CustomCSS.IsNull() {
+		data.CustomCSS = types.StringValue(page.CustomCSS)
+	}
+	if page.GoogleAnalyticsID != "" || !data.GoogleAnalyticsID.IsNull() {
+		data.GoogleAnalyticsID = types.StringValue(page.GoogleAnalyticsID)
+	}
+
+	// An uploaded data URL comes back as the URL of the stored file, which
+	// is not drift
+	if !strings.HasPrefix(data.Icon.ValueString(), "data:") || !strings.HasPrefix(page.Icon, "/upload/") {
+		data.Icon = types.StringValue(page.Icon)
+	}
+
+	// Public groups are compared in order, so groups and monitors reordered
+	// in the UI show up as drift
+	groups, err := r.client.GetPublicGroups(ctx, slug)
+	if errors.Is(err, uptimekuma.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Status page %q no longer exists, removing it from state", slug))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read public groups, got error: %s", err))
+		return
+	}
+	publicGroup, diags := publicGroupsToModel(ctx, groups)
+	resp.Diagnostics.Append(diags...)
+	data.PublicGroup = publicGroup
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	var data StatusPageResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	slug := data.ID.ValueString()
+
+	groups, diags := publicGroupsFromModel(ctx, data.PublicGroup)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// saveStatusPage replaces the domain names too, so send the current ones
+	// along to keep them
+	current, err := r.client.GetStatusPage(ctx, slug)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read status page, got error: %s", err))
+		return
+	}
+	currentGroups, err := r.client.GetPublicGroups(ctx, slug)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read public groups, got error: %s", err))
+		return
+	}
+	reusePublicGroupIDs(groups, currentGroups)
+
+	page := statusPageFromModel(&data)
+	page.DomainNameList = current.DomainNameList
+
+	// Update status page via API
+	if _, err := r.client.SaveStatusPage(ctx, slug, page, groups); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update status page, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StatusPageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	var data StatusPageResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	slug := data.ID.ValueString()
+
+	// Delete status page via API
+	err := r.client.DeleteStatusPage(ctx, slug)
+	if errors.Is(err, uptimekuma.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Status page %q was already deleted", slug))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete status page, got error: %s", err))
+		return
+	}
+}
+
+func (r *StatusPageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !clientConfigured(r.client, &resp.Diagnostics) {
+		return
+	}
+
+	// Import by slug; verify the status page exists
+	_, err := r.client.GetStatusPage(ctx, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find status page with slug %q: %s", req.ID, err))
+		return
+	}
+
+	// Set the ID in state
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// statusPageFromModel builds the status page to send to Uptime Kuma. An
+// unknown icon is sent empty, so Uptime Kuma uses its default.
+func statusPageFromModel(data *StatusPageResourceModel) *uptimekuma.StatusPage {
+	return &uptimekuma.StatusPage{
+		Slug:                  data.Slug.ValueString(),
+		Title:                 data.Title.ValueString(),
+		Description:           data.Description.ValueString(),
+		Icon:                  data.Icon.ValueString(),
+		Theme:                 data.Theme.ValueString(),
+		ShowTags:              data.ShowTags.ValueBool(),
+		CustomCSS:             data.CustomCSS.ValueString(),
+		FooterText:            data.FooterText.ValueString(),
+		ShowPoweredBy:         data.ShowPoweredBy.ValueBool(),
+		ShowCertificateExpiry: data.ShowCertificateExpiry.ValueBool(),
+		GoogleAnalyticsID:     data.GoogleAnalyticsID.ValueString(),
+	}
+}
+
+// publicGroupsFromModel converts the public_group blocks of a plan
+func publicGroupsFromModel(ctx context.Context, list types.List) ([]uptimekuma.PublicGroup, diag.Diagnostics) {
+	if list.IsNull() || list.IsUnknown() {
+		return nil, nil
+	}
+
+	var models []StatusPagePublicGroupModel
+	diags := list.ElementsAs(ctx, &models, false)
+
+	groups := make([]uptimekuma.PublicGroup, len(models))
+	for i, model := range models {
+		var monitors []StatusPagePublicMonitorModel
+		if !model.Monitor.IsNull() && !model.Monitor.IsUnknown() {
+			diags.Append(model.Monitor.ElementsAs(ctx, &monitors, false)...)
+		}
+
+		groups[i] = uptimekuma.PublicGroup{Name: model.Name.ValueString()}
+		for _, monitor := range monitors {
+			groups[i].Monitors = append(groups[i].Monitors, uptimekuma.PublicMonitor{
+				ID:      int(monitor.ID.ValueInt64()),
+				SendURL: monitor.SendURL.ValueBool(),
+			})
+		}
+	}
+	return groups, diags
+}
+
+// publicGroupsToModel converts the public groups of a status page to
+// public_group blocks
+func publicGroupsToModel(ctx context.Context, groups []uptimekuma.PublicGroup) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	models := make([]StatusPagePublicGroupModel, len(groups))
+	for i, group := range groups {
+		monitors := make([]StatusPagePublicMonitorModel, len(group.Monitors))
+		for j, monitor := range group.Monitors {
+			monitors[j] = StatusPagePublicMonitorModel{
+				ID:      types.Int64Value(int64(monitor.ID)),
+				SendURL: types.BoolValue(monitor.SendURL),
+			}
+		}
+
+		list, listDiags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: statusPagePublicMonitorAttrTypes}, monitors)
+		diags.Append(listDiags...)
+		models[i] = StatusPagePublicGroupModel{
+			Name:    types.StringValue(group.Name),
+			Monitor: list,
+		}
+	}
+
+	list, listDiags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: statusPagePublicGroupAttrTypes}, models)
+	diags.Append(listDiags...)
+	return list, diags
+}
+
+// reusePublicGroupIDs gives groups the IDs of the current groups with the
+// same name, so saving updates them in place instead of recreating them
+func reusePublicGroupIDs(groups, current []uptimekuma.PublicGroup) {
+	used := make(map[int]bool, len(current))
+	for i := range groups {
+		for _, existing := range current {
+			if existing.Name == groups[i].Name && !used[existing.ID] {
+				groups[i].ID = existing.ID
+				used[existing.ID] = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
+)
+
+func TestStatusPageResource_CRUD(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	r := &StatusPageResource{client: client}
+	s := testResourceSchema(t, r)
+
+	plan := StatusPageResourceModel{
+		ID:                    types.StringUnknown(),
+		Slug:                  types.StringValue("public"),
+		Title:                 types.StringValue("Public Status"),
+		Description:           types.StringNull(),
+		Theme:                 types.StringValue("dark"),
+		FooterText:            types.StringValue("Operated by ops"),
+		CustomCSS:             types.StringNull(),
+		ShowTags:              types.BoolValue(false),
+		ShowPoweredBy:         types.BoolValue(false),
+		ShowCertificateExpiry: types.BoolValue(true),
+		GoogleAnalyticsID:     types.StringNull(),
+		Icon:                  types.StringUnknown(),
+		PublicGroup:           testPublicGroups(t),
+	}
+
+	// Create
+	createResp := resource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, resource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created StatusPageResourceModel
+	createResp.State.Get(ctx, &created)
+	if created.ID.ValueString() != "public" || created.Icon.ValueString() != "/icon.svg" {
+		t.Fatalf("unexpected state after create: %+v", created)
+	}
+	config, ok := server.StatusPage("public")
+	if !ok {
+		t.Fatal("status page not stored on server")
+	}
+	if config["theme"] != "dark" || config["footerText"] != "Operated by ops" || config["showPoweredBy"] != false || config["showCertificateExpiry"] != true {
+		t.Errorf("unexpected configuration on server: %v", config)
+	}
+
+	// Read keeps unset optional strings null
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.Equal(createResp.State.Raw) {
+		t.Errorf("expected no drift after create, got %v, want %v", readResp.State.Raw, createResp.State.Raw)
+	}
+
+	// Update keeps the domain names configured outside Terraform
+	page, err := client.GetStatusPage(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetStatusPage: %s", err)
+	}
+	page.DomainNameList = []string{"status.example.com"}
+	if _, err := client.SaveStatusPage(ctx, "public", page, nil); err != nil {
+		t.Fatalf("SaveStatusPage: %s", err)
+	}
+
+	plan = created
+	plan.Title = types.StringValue("Service Status")
+	plan.Description = types.StringValue("All our services")
+	plan.Icon = types.StringValue("data:image/png;base64,iVBORw0KGgo=")
+	updateResp := resource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: testPlan(t, s, &plan), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+	config, _ = server.StatusPage("public")
+	if config["title"] != "Service Status" || config["description"] != "All our services" {
+		t.Errorf("expected configuration to be updated on server, got %v", config)
+	}
+	if icon, _ := config["icon"].(string); !strings.HasPrefix(icon, "/upload/") {
+		t.Errorf("expected icon to be uploaded, got %q", icon)
+	}
+	if config["domainNameList"] == nil || len(config["domainNameList"].([]interface{})) != 1 {
+		t.Errorf("expected domain names to be kept, got %v", config["domainNameList"])
+	}
+
+	// The uploaded icon is not drift
+	readResp = resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read after update: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.Equal(updateResp.State.Raw) {
+		t.Errorf("expected no drift after update, got %v, want %v", readResp.State.Raw, updateResp.State.Raw)
+	}
+
+	// Delete
+	deleteResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+	}
+	if _, ok := server.StatusPage("public"); ok {
+		t.Error("expected status page to be deleted on server")
+	}
+
+	// Read after delete removes the resource from state
+	goneResp := resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &goneResp)
+	if goneResp.Diagnostics.HasError() {
+		t.Fatalf("Read after delete: %v", goneResp.Diagnostics)
+	}
+	if !goneResp.State.Raw.IsNull() {
+		t.Error("expected resource to be removed from state")
+	}
+
+	// Deleting a status page that is already gone succeeds
+	againResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &againResp)
+	if againResp.Diagnostics.HasError() {
+		t.Errorf("Delete of deleted status page: %v", againResp.Diagnostics)
+	}
+}
+
+func TestStatusPageResource_ImportState(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &StatusPageResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	server.AddStatusPage("internal", "Internal Status")
+
+	importResp := resource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "internal"}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("ImportState: %v", importResp.Diagnostics)
+	}
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	var imported StatusPageResourceModel
+	readResp.State.Get(ctx, &imported)
+	if imported.Slug.ValueString() != "internal" || imported.Title.ValueString() != "Internal Status" ||
+		imported.Theme.ValueString() != "auto" || !imported.ShowPoweredBy.ValueBool() || !imported.Description.IsNull() ||
+		len(imported.PublicGroup.Elements()) != 0 {
+		t.Errorf("unexpected state after import: %+v", imported)
+	}
+
+	// Importing a status page that does not exist fails
+	missingResp := resource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "missing"}, &missingResp)
+	if !missingResp.Diagnostics.HasError() {
+		t.Error("expected error importing a missing status page")
+	}
+}
+
+func TestStatusPageResource_PublicGroups(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	r := &StatusPageResource{client: client}
+	s := testResourceSchema(t, r)
+
+	api := server.AddMonitor(map[string]interface{}{"name": "api", "type": "http", "url": "https://api.example.com"})
+	web := server.AddMonitor(map[string]interface{}{"name": "web", "type": "http", "url": "https://example.com"})
+	db := server.AddMonitor(map[string]interface{}{"name": "db", "type": "port", "hostname": "db.example.com", "port": 5432})
+
+	plan := StatusPageResourceModel{
+		ID:                    types.StringUnknown(),
+		Slug:                  types.StringValue("public"),
+		Title:                 types.StringValue("Public Status"),
+		Theme:                 types.StringValue("auto"),
+		ShowTags:              types.BoolValue(false),
+		ShowPoweredBy:         types.BoolValue(true),
+		ShowCertificateExpiry: types.BoolValue(false),
+		Icon:                  types.StringUnknown(),
+		PublicGroup: testPublicGroups(t,
+			uptimekuma.PublicGroup{Name: "Websites", Monitors: []uptimekuma.PublicMonitor{{ID: web, SendURL: true}, {ID: api}}},
+			uptimekuma.PublicGroup{Name: "Backend", Monitors: []uptimekuma.PublicMonitor{{ID: db}}},
+		),
+	}
+
+	// Create saves the groups in order
+	createResp := resource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, resource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+	groups, err := client.GetPublicGroups(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetPublicGroups: %s", err)
+	}
+	if len(groups) != 2 || groups[0].Name != "Websites" || groups[1].Name != "Backend" ||
+		len(groups[0].Monitors) != 2 || groups[0].Monitors[0] != (uptimekuma.PublicMonitor{ID: web, SendURL: true}) || groups[0].Monitors[1].ID != api {
+		t.Fatalf("unexpected public groups on server: %+v", groups)
+	}
+	websitesID := groups[0].ID
+
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.Equal(createResp.State.Raw) {
+		t.Errorf("expected no drift after create, got %v, want %v", readResp.State.Raw, createResp.State.Raw)
+	}
+
+	// Reordering the groups in the UI is drift
+	page, err := client.GetStatusPage(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetStatusPage: %s", err)
+	}
+	if _, err := client.SaveStatusPage(ctx, "public", page, []uptimekuma.PublicGroup{groups[1], groups[0]}); err != nil {
+		t.Fatalf("SaveStatusPage: %s", err)
+	}
+	readResp = resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read after reorder: %v", readResp.Diagnostics)
+	}
+	var read StatusPageResourceModel
+	readResp.State.Get(ctx, &read)
+	var names []string
+	for _, group := range testPublicGroupsFromState(t, read.PublicGroup) {
+		names = append(names, group.Name)
+	}
+	if strings.Join(names, ",") != "Backend,Websites" {
+		t.Errorf("expected reordered groups in state, got %v", names)
+	}
+
+	// Update restores the configured order, keeps the IDs of the groups and
+	// removes groups that are no longer configured
+	plan.ID = types.StringValue("public")
+	plan.Icon = types.StringValue("/icon.svg")
+	plan.PublicGroup = testPublicGroups(t,
+		uptimekuma.PublicGroup{Name: "Websites", Monitors: []uptimekuma.PublicMonitor{{ID: api}, {ID: web}}},
+	)
+	updateResp := resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: testPlan(t, s, &plan), State: readResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+	groups, err = client.GetPublicGroups(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetPublicGroups: %s", err)
+	}
+	want := []uptimekuma.PublicGroup{{ID: websitesID, Name: "Websites", Monitors: []uptimekuma.PublicMonitor{{ID: api}, {ID: web}}}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("expected public groups %+v, got %+v", want, groups)
+	}
+
+	readResp = resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read after update: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.Equal(updateResp.State.Raw) {
+		t.Errorf("expected no drift after update, got %v, want %v", readResp.State.Raw, updateResp.State.Raw)
+	}
+}
+
+// testPublicGroups builds public_group blocks holding the given groups
+func testPublicGroups(t *testing.T, groups ...uptimekuma.PublicGroup) types.List {
+	t.Helper()
+
+	list, diags := publicGroupsToModel(context.Background(), groups)
+	if diags.HasError() {
+		t.Fatalf("publicGroupsToModel: %v", diags)
+	}
+	return list
+}
+
+// testPublicGroupsFromState converts public_group blocks back to groups
+func testPublicGroupsFromState(t *testing.T, list types.List) []uptimekuma.PublicGroup {
+	t.Helper()
+
+	groups, diags := publicGroupsFromModel(context.Background(), list)
+	if diags.HasError() {
+		t.Fatalf("publicGroupsFromModel: %v", diags)
+	}
+	return groups
+}
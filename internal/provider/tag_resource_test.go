@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestTagResource_CRUD(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &TagResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	plan := TagResourceModel{
+		ID:    types.StringUnknown(),
+		Name:  types.StringValue("env:prod"),
+		Color: types.StringValue("#DC2626"),
+	}
+
+	// Create
+	createResp := resource.CreateResponse{State: testEmptyState(s)}
+	r.Create(ctx, resource.CreateRequest{Plan: testPlan(t, s, &plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	var created TagResourceModel
+	createResp.State.Get(ctx, &created)
+	id, err := strconv.Atoi(created.ID.ValueString())
+	if err != nil {
+		t.Fatalf("unexpected tag ID %q", created.ID.ValueString())
+	}
+	if _, ok := server.Tag(id); !ok {
+		t.Fatalf("tag %d not stored on server", id)
+	}
+
+	// Update
+	plan = created
+	plan.Color = types.StringValue("#2563EB")
+	updateResp := resource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: testPlan(t, s, &plan), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", updateResp.Diagnostics)
+	}
+	if tag, _ := server.Tag(id); tag["color"] != "#2563EB" {
+		t.Errorf("expected colour to be updated on server, got %v", tag["color"])
+	}
+
+	// Delete
+	deleteResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+	}
+	if _, ok := server.Tag(id); ok {
+		t.Error("expected tag to be deleted on server")
+	}
+
+	// Read after delete removes the resource from state
+	goneResp := resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &goneResp)
+	if goneResp.Diagnostics.HasError() {
+		t.Fatalf("Read after delete: %v", goneResp.Diagnostics)
+	}
+	if !goneResp.State.Raw.IsNull() {
+		t.Error("expected resource to be removed from state")
+	}
+
+	// Deleting a tag that is already gone succeeds
+	againResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &againResp)
+	if againResp.Diagnostics.HasError() {
+		t.Errorf("Delete of deleted tag: %v", againResp.Diagnostics)
+	}
+}
+
+func TestTagResource_Import(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	r := &TagResource{client: newTestClient(t, server)}
+	s := testResourceSchema(t, r)
+
+	id := server.AddTag("team:web", "#059669")
+
+	importResp := resource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: strconv.Itoa(id)}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("ImportState: %v", importResp.Diagnostics)
+	}
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+
+	var imported TagResourceModel
+	readResp.State.Get(ctx, &imported)
+	if imported.Name.ValueString() != "team:web" || imported.Color.ValueString() != "#059669" {
+		t.Errorf("unexpected state after import: %+v", imported)
+	}
+
+	// Importing a tag that does not exist fails
+	missingResp := resource.ImportStateResponse{State: testEmptyState(s)}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "999"}, &missingResp)
+	if !missingResp.Diagnostics.HasError() {
+		t.Error("expected error importing a missing tag")
+	}
+}
@@ -0,0 +1,46 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B SHA1 vectors, truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d): %s", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCode_SecretFormatting(t *testing.T) {
+	at := time.Unix(59, 0)
+	got, err := Code("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", at)
+	if err != nil {
+		t.Fatalf("Code: %s", err)
+	}
+	if got != "287082" {
+		t.Errorf("expected lower case, spaced secret to work, got %s", got)
+	}
+
+	if _, err := Code("not base32!", at); err == nil {
+		t.Error("expected error for invalid secret")
+	}
+}
@@ -3,18 +3,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
-	"github.com/j0r15/terraform-provider-uptimekuma/internal/provider"
+	"github.com/j0r15/terraform-provider-uptimekuma/uptimekuma"
 )
 
 func main() {
 	fmt.Println("Testing notification creation...")
 
+	ctx := context.Background()
+
 	// Create client
-	client, err := provider.NewClient("http://localhost:3001", "admin", "cF96H*L9LA3*HiWhx")
+	client, err := uptimekuma.NewClient(ctx, "http://localhost:3001", "admin", "cF96H*L9LA3*HiWhx")
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -28,7 +31,7 @@ func main() {
 
 	// Test creating a notification
 	fmt.Println("📧 Creating test notification...")
-	testNotif := &provider.Notification{
+	testNotif := &uptimekuma.Notification{
 		Name:          "Test Notification",
 		Type:          "discord",
 		IsDefault:     false,
@@ -38,7 +41,7 @@ func main() {
 		},
 	}
 
-	createdNotif, err := client.CreateNotification(testNotif)
+	createdNotif, err := client.CreateNotification(ctx, testNotif)
 	if err != nil {
 		log.Printf("❌ Failed to create notification: %v", err)
 	} else {
@@ -47,7 +50,7 @@ func main() {
 
 	// List all notifications again
 	fmt.Println("📋 Getting all notifications after creation...")
-	notifications, err := client.GetNotifications()
+	notifications, err := client.GetNotifications(ctx)
 	if err != nil {
 		log.Printf("❌ Failed to get notifications: %v", err)
 	} else {
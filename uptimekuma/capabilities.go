@@ -0,0 +1,89 @@
+package uptimekuma
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// monitorTypeVersions lists the Uptime Kuma release that introduced each
+// monitor type newer than 1.22. Types not listed work on every supported
+// server.
+var monitorTypeVersions = map[string]string{
+	"group":          "1.23.0",
+	"json-query":     "1.23.0",
+	"kafka-producer": "1.23.0",
+	"real-browser":   "1.23.0",
+	"tailscale-ping": "1.23.0",
+	"rabbitmq":       "2.0.0",
+	"smtp":           "2.0.0",
+	"snmp":           "2.0.0",
+}
+
+// MonitorTypeVersion returns the Uptime Kuma release that introduced the
+// monitor type, or false if the type works on every supported server
+func MonitorTypeVersion(monitorType string) (string, bool) {
+	version, ok := monitorTypeVersions[monitorType]
+	return version, ok
+}
+
+// ServerVersion returns the Uptime Kuma version reported by the server's
+// info event, or "" if it has not been reported
+func (c *Client) ServerVersion() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.serverVersion
+}
+
+// CheckVersion returns an error naming the feature when the connected server
+// is older than minVersion. An unknown server version passes, so a server
+// that hides its version, or a client that has not connected yet, is never
+// locked out.
+func (c *Client) CheckVersion(feature, minVersion string) error {
+	version := c.ServerVersion()
+	if version == "" || compareVersions(version, minVersion) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%s requires Uptime Kuma %s or later, but the server runs %s", feature, minVersion, version)
+}
+
+// compareVersions compares the numeric parts of two versions like "1.23.16"
+// or "2.0.0-beta.2", returning -1, 0 or 1. Pre-release suffixes are ignored,
+// so a beta counts as the release it leads up to.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
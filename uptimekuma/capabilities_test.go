@@ -0,0 +1,24 @@
+package uptimekuma
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.23.16", "1.23.0", 1},
+		{"1.22.1", "1.23.0", -1},
+		{"1.23.0", "1.23.0", 0},
+		{"2.0.0-beta.2", "2.0.0", 0},
+		{"2.0.0-beta.2", "1.23.16", 1},
+		{"v1.23", "1.23.0", 0},
+		{"1.9.0", "1.10.0", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package uptimekuma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Cassette holds recorded Socket.IO sessions, one per connection, for
+// replaying them in tests
+type Cassette struct {
+	Sessions []CassetteSession `json:"sessions"`
+}
+
+// CassetteSession is the frames of one connection in the order they were
+// sent and received
+type CassetteSession struct {
+	URL    string          `json:"url"`
+	Frames []CassetteFrame `json:"frames"`
+}
+
+// CassetteFrame is one recorded frame, with secrets masked as in the frame log
+type CassetteFrame struct {
+	Direction string `json:"direction"` // "sent" or "received"
+	Frame     string `json:"frame"`
+}
+
+// LoadCassette reads a cassette written by Recorder.Save
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// isHeartbeat reports whether a frame is an Engine.IO ping or pong, which
+// depend on timing and are left out of cassettes. The Replayer sends its own
+// pings instead.
+func isHeartbeat(frame string) bool {
+	return frame == "2" || frame == "3"
+}
+
+// Recorder is a Transport that records the frames of every connection it
+// opens through another Transport. Passwords, tokens and other secrets are
+// masked, so cassettes recorded against a real instance can be committed.
+type Recorder struct {
+	transport Transport
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder records the sessions opened through transport, or through a
+// WebSocket with default settings if transport is nil
+func NewRecorder(transport Transport) *Recorder {
+	if transport == nil {
+		transport = &websocketTransport{dialer: websocket.DefaultDialer}
+	}
+	return &Recorder{transport: transport}
+}
+
+func (r *Recorder) Dial(ctx context.Context, url string, header http.Header) (Conn, error) {
+	conn, err := r.transport.Dial(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Sessions = append(r.cassette.Sessions, CassetteSession{URL: url})
+	return &recordingConn{Conn: conn, recorder: r, session: len(r.cassette.Sessions) - 1}, nil
+}
+
+// record appends a frame to a session
+func (r *Recorder) record(session int, direction, frame string) {
+	if isHeartbeat(frame) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &r.cassette.Sessions[session]
+	s.Frames = append(s.Frames, CassetteFrame{Direction: direction, Frame: redactFrame(frame)})
+}
+
+// Save writes the sessions recorded so far to a cassette file
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encoding cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing cassette: %w", err)
+	}
+	return nil
+}
+
+// recordingConn records the frames passing through a connection
+type recordingConn struct {
+	Conn
+	recorder *Recorder
+	session  int
+}
+
+func (c *recordingConn) ReadFrame() (string, error) {
+	frame, err := c.Conn.ReadFrame()
+	if err == nil {
+		c.recorder.record(c.session, "received", frame)
+	}
+	return frame, err
+}
+
+func (c *recordingConn) WriteFrame(frame string) error {
+	err := c.Conn.WriteFrame(frame)
+	if err == nil {
+		c.recorder.record(c.session, "sent", frame)
+	}
+	return err
+}
+
+// Replayer is a Transport that plays back the sessions of a cassette instead
+// of connecting to a server. Every Dial opens the next recorded session.
+// Received frames are handed out once the frames recorded before them have
+// been sent, and every frame the client sends must match the recording after
+// masking secrets, so a change in what the client sends fails the test.
+// Like a server, the Replayer pings at the interval from the recorded open
+// packet and expects a pong for every ping.
+type Replayer struct {
+	mu       sync.Mutex
+	sessions []CassetteSession
+}
+
+// NewReplayer plays back the sessions of the cassette in order
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{sessions: cassette.Sessions}
+}
+
+func (r *Replayer) Dial(ctx context.Context, url string, header http.Header) (Conn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.sessions) == 0 {
+		return nil, errors.New("replay: cassette has no more sessions")
+	}
+	session := r.sessions[0]
+	r.sessions = r.sessions[1:]
+
+	c := &replayConn{frames: session.Frames}
+	c.cond = sync.NewCond(&c.mu)
+	return c, nil
+}
+
+// replayConn plays back the frames of one session
+type replayConn struct {
+	mu            sync.Mutex
+	cond          *sync.Cond // Signalled when pos, closed, a deadline or a ping is due
+	frames        []CassetteFrame
+	pos           int
+	closed        bool
+	readDeadline  time.Time
+	writeDeadline time.Time
+	pingInterval  time.Duration // From the open packet; zero until it was read
+	nextPing      time.Time
+	pendingPongs  int // Pings sent that have not been answered yet
+}
+
+func (c *replayConn) ReadFrame() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		switch {
+		case c.closed:
+			return "", net.ErrClosed
+		case expired(c.readDeadline):
+			return "", os.ErrDeadlineExceeded
+		case c.pos < len(c.frames) && c.frames[c.pos].Direction == "received":
+			frame := c.frames[c.pos].Frame
+			c.pos++
+			if strings.HasPrefix(frame, "0") {
+				c.startPings(frame[1:])
+			}
+			c.cond.Broadcast()
+			return frame, nil
+		case c.pingInterval > 0 && expired(c.nextPing):
+			c.pendingPongs++
+			c.schedulePing()
+			return "2", nil
+		}
+		// Wait for the client to send what was recorded before the next
+		// received frame, or idle like a quiet server after the last one
+		c.cond.Wait()
+	}
+}
+
+// startPings starts pinging at the interval from the Engine.IO open packet.
+// Callers hold c.mu.
+func (c *replayConn) startPings(open string) {
+	var handshake struct {
+		PingInterval int `json:"pingInterval"`
+	}
+	if err := json.Unmarshal([]byte(open), &handshake); err != nil || handshake.PingInterval <= 0 {
+		return
+	}
+	c.pingInterval = time.Duration(handshake.PingInterval) * time.Millisecond
+	c.schedulePing()
+}
+
+// schedulePing sets the time of the next ping and wakes ReadFrame then.
+// Callers hold c.mu.
+func (c *replayConn) schedulePing() {
+	c.nextPing = time.Now().Add(c.pingInterval)
+	time.AfterFunc(c.pingInterval, func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+}
+
+func (c *replayConn) WriteFrame(frame string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Pongs answer the replayer's own pings and are not in the cassette
+	if frame == "3" {
+		if c.pendingPongs == 0 {
+			return errors.New("replay: sent a pong without a ping")
+		}
+		c.pendingPongs--
+		return nil
+	}
+
+	// Frames received before this one was sent must be read first
+	for !c.closed && !expired(c.writeDeadline) && c.pos < len(c.frames) && c.frames[c.pos].Direction == "received" {
+		c.cond.Wait()
+	}
+
+	switch {
+	case c.closed:
+		return net.ErrClosed
+	case expired(c.writeDeadline):
+		return os.ErrDeadlineExceeded
+	case c.pos >= len(c.frames):
+		return fmt.Errorf("replay: sent %s after the end of the recorded session", redactFrame(frame))
+	}
+
+	if got, want := redactFrame(frame), c.frames[c.pos].Frame; got != want {
+		return fmt.Errorf("replay: sent %s, cassette expects %s", got, want)
+	}
+	c.pos++
+	c.cond.Broadcast()
+
+	return nil
+}
+
+func (c *replayConn) SetReadDeadline(t time.Time) error {
+	c.setDeadline(&c.readDeadline, t)
+	return nil
+}
+
+func (c *replayConn) SetWriteDeadline(t time.Time) error {
+	c.setDeadline(&c.writeDeadline, t)
+	return nil
+}
+
+// setDeadline updates a deadline and wakes the waiters when it passes
+func (c *replayConn) setDeadline(deadline *time.Time, t time.Time) {
+	c.mu.Lock()
+	*deadline = t
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if !t.IsZero() {
+		time.AfterFunc(time.Until(t), func() {
+			c.mu.Lock()
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		})
+	}
+}
+
+func (c *replayConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.cond.Broadcast()
+	return nil
+}
+
+// expired reports whether a deadline is set and has passed
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
@@ -0,0 +1,217 @@
+package uptimekuma
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+// The cassette in testdata was recorded against the fake server in
+// internal/fakekuma, so it tests the Recorder and Replayer, not how a real
+// Uptime Kuma answers. Re-record it with
+//
+//	go test ./uptimekuma -run TestReplayer_FakeServerCassette -record
+var record = flag.Bool("record", false, "re-record the cassette in testdata")
+
+// monitorLifecycle runs the same calls against a live or replayed server and
+// returns the ID of the monitor it created
+func monitorLifecycle(t *testing.T, client *Client) int {
+	t.Helper()
+	ctx := context.Background()
+
+	created, err := client.CreateMonitor(ctx, &Monitor{
+		Name:     "example",
+		Type:     "http",
+		URL:      "https://example.com",
+		Interval: 60,
+		Timeout:  30,
+		Active:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateMonitor: %s", err)
+	}
+
+	created.Name = "renamed"
+	if _, err := client.UpdateMonitor(ctx, created); err != nil {
+		t.Fatalf("UpdateMonitor: %s", err)
+	}
+
+	monitor, err := client.GetMonitor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if monitor.Name != "renamed" {
+		t.Errorf("expected renamed monitor, got %q", monitor.Name)
+	}
+
+	if err := client.DeleteMonitor(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteMonitor: %s", err)
+	}
+	return created.ID
+}
+
+// recordCassette records the monitor lifecycle against the fake server
+func recordCassette(t *testing.T) (string, int) {
+	t.Helper()
+
+	server := fakekuma.NewServer(t)
+	recorder := NewRecorder(nil)
+
+	client, err := NewClient(context.Background(), server.URL, server.Username, server.Password, WithTransport(recorder))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	id := monitorLifecycle(t, client)
+	client.Close()
+
+	path := filepath.Join(t.TempDir(), "monitor_lifecycle.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	return path, id
+}
+
+func TestRecorder_MasksSecrets(t *testing.T) {
+	path, _ := recordCassette(t)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{fakekuma.DefaultPassword, fakekuma.DefaultToken} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains secret %q", secret)
+		}
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %s", err)
+	}
+	if len(cassette.Sessions) != 1 {
+		t.Fatalf("expected one session, got %d", len(cassette.Sessions))
+	}
+	for _, frame := range cassette.Sessions[0].Frames {
+		if isHeartbeat(frame.Frame) {
+			t.Errorf("cassette contains heartbeat frame %q", frame.Frame)
+		}
+	}
+}
+
+func TestReplayer_ReplaysSession(t *testing.T) {
+	path, id := recordCassette(t)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %s", err)
+	}
+
+	// The URL and credentials are not checked against the recording; the
+	// login frame only has to match once the password is masked
+	client, err := NewClient(context.Background(), "http://kuma.invalid", fakekuma.DefaultUsername, "other-password",
+		WithTransport(NewReplayer(cassette)))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	if got := monitorLifecycle(t, client); got != id {
+		t.Errorf("expected replayed monitor ID %d, got %d", id, got)
+	}
+}
+
+func TestReplayer_DetectsChangedRequests(t *testing.T) {
+	path, _ := recordCassette(t)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %s", err)
+	}
+
+	client, err := NewClient(context.Background(), "http://kuma.invalid", fakekuma.DefaultUsername, fakekuma.DefaultPassword,
+		WithTransport(NewReplayer(cassette)), WithTimeout(time.Second), WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	_, err = client.CreateMonitor(context.Background(), &Monitor{Name: "different", Type: "ping", Hostname: "example.com", Interval: 60, Active: true})
+	if err == nil {
+		t.Fatal("expected error for a request the cassette does not contain")
+	}
+	if !strings.Contains(err.Error(), "cassette expects") {
+		t.Errorf("expected replay mismatch in error, got: %s", err)
+	}
+}
+
+func TestReplayer_NoMoreSessions(t *testing.T) {
+	replayer := NewReplayer(&Cassette{})
+
+	_, err := NewClient(context.Background(), "http://kuma.invalid", "admin", "password", WithTransport(replayer), WithRetry(0, 0))
+	if err == nil || !strings.Contains(err.Error(), "no more sessions") {
+		t.Errorf("expected no more sessions error, got: %v", err)
+	}
+}
+
+// countingTransport counts the connections opened through a Transport
+type countingTransport struct {
+	Transport
+	dials atomic.Int32
+}
+
+func (t *countingTransport) Dial(ctx context.Context, url string, header http.Header) (Conn, error) {
+	t.dials.Add(1)
+	return t.Transport.Dial(ctx, url, header)
+}
+
+func TestReplayer_FakeServerCassette(t *testing.T) {
+	path := filepath.Join("testdata", "fakekuma_lifecycle.json")
+
+	// The short ping interval in the open packet makes the replayer ping
+	// several times while the test idles below
+	if *record {
+		server := fakekuma.NewServer(t, fakekuma.WithPingInterval(100*time.Millisecond, 100*time.Millisecond))
+		recorder := NewRecorder(nil)
+		client, err := NewClient(context.Background(), server.URL, server.Username, server.Password, WithTransport(recorder))
+		if err != nil {
+			t.Fatalf("NewClient: %s", err)
+		}
+		monitorLifecycle(t, client)
+		client.Close()
+		if err := recorder.Save(path); err != nil {
+			t.Fatalf("Save: %s", err)
+		}
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %s", err)
+	}
+	transport := &countingTransport{Transport: NewReplayer(cassette)}
+	client, err := NewClient(context.Background(), "http://kuma.invalid", fakekuma.DefaultUsername, fakekuma.DefaultPassword,
+		WithTransport(transport))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+	setReconnectDelay(client, 10*time.Millisecond)
+
+	if id := monitorLifecycle(t, client); id == 0 {
+		t.Error("expected the monitor ID from the cassette")
+	}
+
+	// A replayer that never pings would let the read deadline of ping
+	// interval plus timeout pass, and the client would reconnect
+	time.Sleep(500 * time.Millisecond)
+	if dials := transport.dials.Load(); dials != 1 {
+		t.Errorf("expected the replayed session to stay open, got %d dials", dials)
+	}
+}
@@ -0,0 +1,1879 @@
+package uptimekuma
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/totp"
+)
+
+// DefaultTimeout is how long a call waits for its acknowledgement when the
+// context has no deadline of its own
+const DefaultTimeout = 30 * time.Second
+
+// ErrTOTPRequired is returned by NewClient when the account has two-factor
+// authentication enabled but no TOTP secret or code was configured
+var ErrTOTPRequired = errors.New("two-factor authentication is enabled for this account")
+
+var (
+	// errConnectionLost is returned when the connection drops before a call is acknowledged
+	errConnectionLost = errors.New("connection lost")
+	// errClientClosed is returned for calls made after Close
+	errClientClosed = errors.New("client closed")
+)
+
+// idempotentEvents are safe to resend when the connection drops or the
+// acknowledgement is lost
+var idempotentEvents = map[string]bool{
+	"getMonitorList":   true,
+	"editMonitor":      true,
+	"deleteMonitor":    true,
+	"getTags":          true,
+	"editTag":          true,
+	"deleteTag":        true,
+	"editMonitorTag":   true,
+	"deleteMonitorTag": true,
+	"getStatusPage":    true,
+	"deleteStatusPage": true,
+}
+
+// Client represents the Uptime Kuma API client
+type Client struct {
+	BaseURL              string
+	SocketPath           string // Socket.IO endpoint path; derived from BaseURL when empty
+	Username             string
+	Password             string
+	HTTPClient           *http.Client
+	TLSConfig            *tls.Config   // Used by both the WebSocket dialer and HTTPClient; nil for Go defaults
+	Headers              http.Header   // Extra headers for the WebSocket upgrade and HTTP requests
+	ProxyURL             *url.URL      // HTTP or SOCKS5 proxy; nil uses the proxy environment variables
+	TOTPSecret           string        // Base32 secret to generate two-factor codes from
+	TOTPCode             string        // Fixed two-factor code, used when TOTPSecret is empty
+	TokenCachePath       string        // File to keep session tokens in between runs; empty disables the cache
+	Transport            Transport     // Opens the Socket.IO connections; nil dials a WebSocket
+	Timeout              time.Duration // How long each attempt of a call waits for its acknowledgement
+	MaxRetries           int           // How often rate limited logins and calls, and idempotent calls that got no answer, are repeated
+	RetryBackoff         time.Duration // Delay before the first retry, doubled for every further one
+	conn                 *connection   // Current Socket.IO session
+	connected            bool          // Whether conn is established and authenticated
+	connectedCh          chan struct{} // Closed when connected becomes true
+	reconnecting         bool
+	reconnectDelay       time.Duration // Initial delay between reconnect attempts
+	maxReconnectDelay    time.Duration
+	closed               bool
+	closedCh             chan struct{}   // Closed by Close to stop reconnecting
+	lazy                 bool            // Connect on first use instead of in NewClient
+	logCtx               context.Context // Context frames are logged with
+	started              atomic.Bool     // Whether the first connect succeeded and the lists arrived; set under startMu
+	sessionStarted       bool            // Whether the first connect succeeded; guarded by startMu
+	startMu              sync.Mutex
+	mu                   sync.RWMutex
+	eventID              int
+	responses            map[int]chan SocketResponse
+	respMu               sync.RWMutex
+	userID               int
+	serverVersion        string // From the info event; guarded by mu
+	token                string
+	monitors             map[int]Monitor // Cache for monitors from monitorList event
+	monitorsMu           sync.RWMutex
+	monitorsVersion      listVersion
+	notificationCache    []Notification // Cache for notifications from notificationList event
+	notificationsMu      sync.RWMutex
+	notificationsVersion listVersion
+}
+
+// listVersion counts the updates of a list pushed by the server, so callers
+// can wait for fresh data instead of sleeping
+type listVersion struct {
+	mu      sync.Mutex
+	version uint64
+	changed chan struct{} // Closed and replaced on every update
+}
+
+// current returns the number of updates received so far
+func (v *listVersion) current() uint64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.version
+}
+
+// bump records an update and wakes everyone waiting for it
+func (v *listVersion) bump() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.version++
+	if v.changed != nil {
+		close(v.changed)
+	}
+	v.changed = make(chan struct{})
+}
+
+// waitAfter blocks until an update newer than version has been received
+func (v *listVersion) waitAfter(ctx context.Context, version uint64) error {
+	for {
+		v.mu.Lock()
+		if v.version > version {
+			v.mu.Unlock()
+			return nil
+		}
+		if v.changed == nil {
+			v.changed = make(chan struct{})
+		}
+		changed := v.changed
+		v.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return contextError(ctx)
+		}
+	}
+}
+
+// connection is a single Socket.IO session over a WebSocket
+type connection struct {
+	ws           Conn
+	sid          string        // Engine.IO session ID from the open packet
+	pingInterval time.Duration // How often the server promises to ping
+	pingTimeout  time.Duration // How long past pingInterval a ping may be late
+	done         chan struct{} // Closed when the session ends
+	closeOnce    sync.Once
+	writeMu      sync.Mutex      // Serialises writes; gorilla allows one writer at a time
+	logCtx       context.Context // Carries the SocketLogSubsystem logger; nil disables frame logging
+}
+
+// openPacket is the payload of the Engine.IO open packet
+type openPacket struct {
+	SID          string   `json:"sid"`
+	Upgrades     []string `json:"upgrades"`
+	PingInterval int      `json:"pingInterval"`
+	PingTimeout  int      `json:"pingTimeout"`
+	MaxPayload   int      `json:"maxPayload"`
+}
+
+// resetPingTimeout extends the read deadline until the next ping is due; a
+// server that stays silent past it is treated as gone
+func (cn *connection) resetPingTimeout() {
+	if cn.pingInterval > 0 {
+		cn.ws.SetReadDeadline(time.Now().Add(cn.pingInterval + cn.pingTimeout))
+	}
+}
+
+// close ends the session; it is safe to call more than once
+func (cn *connection) close() {
+	cn.closeOnce.Do(func() {
+		close(cn.done)
+		cn.ws.Close()
+	})
+}
+
+// SocketIOMessage represents a Socket.IO message
+type SocketIOMessage struct {
+	Type      int           `json:"0,omitempty"`
+	Namespace string        `json:"1,omitempty"`
+	Event     string        `json:"2,omitempty"`
+	ID        int           `json:"3,omitempty"`
+	Data      []interface{} `json:"4,omitempty"`
+}
+
+// SocketResponse represents a Socket.IO response
+type SocketResponse struct {
+	Event string        `json:"event"`
+	Data  []interface{} `json:"data"`
+	Error string        `json:"error,omitempty"`
+}
+
+// Monitor represents an Uptime Kuma monitor
+type Monitor struct {
+	ID                  int               `json:"id,omitempty"`
+	Name                string            `json:"name"`
+	Type                string            `json:"type"`
+	URL                 string            `json:"url,omitempty"`
+	Hostname            string            `json:"hostname,omitempty"`
+	Port                int               `json:"port,omitempty"`
+	Interval            int               `json:"interval"`
+	Timeout             int               `json:"timeout"`
+	RetryInterval       int               `json:"retryInterval,omitempty"`
+	ResendInterval      int               `json:"resendInterval,omitempty"`
+	MaxRetries          int               `json:"maxretries,omitempty"`
+	UpsideDown          bool              `json:"upsideDown,omitempty"`
+	MaxRedirects        int               `json:"maxredirects,omitempty"`
+	AcceptedStatusCodes []string          `json:"accepted_statuscodes,omitempty"`
+	FollowRedirect      bool              `json:"follow_redirect,omitempty"`
+	Tags                []MonitorTag      `json:"tags,omitempty"`
+	NotificationIDList  []int             `json:"notificationIDList,omitempty"`
+	Active              bool              `json:"active"`
+	IgnoreTLS           bool              `json:"ignoreTls,omitempty"`
+	HTTPMethod          string            `json:"method,omitempty"`
+	Body                string            `json:"body,omitempty"`
+	Headers             map[string]string `json:"headers,omitempty"`
+	BasicAuthUser       string            `json:"basic_auth_user,omitempty"`
+	BasicAuthPass       string            `json:"basic_auth_pass,omitempty"`
+	Description         string            `json:"description,omitempty"`
+	Parent              int               `json:"parent,omitempty"`
+	Keyword             string            `json:"keyword,omitempty"`
+	InvertKeyword       bool              `json:"invertKeyword,omitempty"`
+	ExpiryNotification  bool              `json:"expiryNotification,omitempty"`
+	PacketSize          int               `json:"packetSize,omitempty"`
+	DNSResolveType      string            `json:"dns_resolve_type,omitempty"`
+	DNSResolveServer    string            `json:"dns_resolve_server,omitempty"`
+	HTTPBodyEncoding    string            `json:"httpBodyEncoding,omitempty"`
+	AuthMethod          string            `json:"authMethod,omitempty"`
+	PushToken           string            `json:"pushToken,omitempty"`
+}
+
+// LoginRequest represents the login request payload
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse represents the login response
+type LoginResponse struct {
+	Token string `json:"token"`
+	OK    bool   `json:"ok"`
+	Msg   string `json:"msg"`
+}
+
+// APIResponse represents a generic API response
+type APIResponse struct {
+	OK  bool   `json:"ok"`
+	Msg string `json:"msg"`
+}
+
+// MonitorResponse represents the response when getting a monitor
+type MonitorResponse struct {
+	Monitor Monitor `json:"monitor"`
+	OK      bool    `json:"ok"`
+	Msg     string  `json:"msg"`
+}
+
+// MonitorsResponse represents the response when getting all monitors
+type MonitorsResponse struct {
+	Monitors []Monitor `json:"monitors"`
+	OK       bool      `json:"ok"`
+	Msg      string    `json:"msg"`
+}
+
+// Notification represents an Uptime Kuma notification
+type Notification struct {
+	ID            int                    `json:"id,omitempty"`
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+	IsDefault     bool                   `json:"isDefault,omitempty"`
+	ApplyExisting bool                   `json:"applyExisting,omitempty"`
+	Active        bool                   `json:"active,omitempty"`
+	UserID        int                    `json:"userId,omitempty"`
+	Config        map[string]interface{} `json:"config,omitempty"`
+}
+
+// NotificationResponse represents the response when getting a notification
+type NotificationResponse struct {
+	Notification Notification `json:"notification"`
+	OK           bool         `json:"ok"`
+	Msg          string       `json:"msg"`
+}
+
+// NotificationsResponse represents the response when getting all notifications
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	OK            bool           `json:"ok"`
+	Msg           string         `json:"msg"`
+}
+
+// NotificationCreateResponse represents the response when creating a notification
+type NotificationCreateResponse struct {
+	ID  int    `json:"id"`
+	OK  bool   `json:"ok"`
+	Msg string `json:"msg"`
+}
+
+// ClientOption configures a Client before it connects
+type ClientOption func(*Client)
+
+// WithTimeout sets how long each attempt of a call waits for Uptime Kuma to
+// answer when the context has no earlier deadline
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithLazyConnect defers connecting and logging in from NewClient to the
+// first request, so a client can be created before the server is reachable
+func WithLazyConnect() ClientOption {
+	return func(c *Client) {
+		c.lazy = true
+	}
+}
+
+// WithSocketPath overrides the path of the Socket.IO endpoint, for reverse
+// proxies that expose it somewhere other than <url path>/socket.io/
+func WithSocketPath(socketPath string) ClientOption {
+	return func(c *Client) {
+		c.SocketPath = socketPath
+	}
+}
+
+// WithTLSConfig sets the TLS configuration for the WebSocket connection and
+// HTTPClient, e.g. for a private CA or client certificates
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.TLSConfig = config
+	}
+}
+
+// WithHeaders adds headers to the WebSocket upgrade request and HTTP
+// requests, e.g. for Cloudflare Access or an OAuth2 proxy in front of Uptime Kuma
+func WithHeaders(headers http.Header) ClientOption {
+	return func(c *Client) {
+		c.Headers = headers
+	}
+}
+
+// WithProxyURL routes the connection through an http or socks5 proxy instead
+// of the one from HTTPS_PROXY/HTTP_PROXY. The WebSocket dialer supports no
+// other schemes.
+func WithProxyURL(proxyURL *url.URL) ClientOption {
+	return func(c *Client) {
+		c.ProxyURL = proxyURL
+	}
+}
+
+// WithTOTPSecret answers two-factor prompts with codes generated from the
+// base32 secret shown when 2FA was set up
+func WithTOTPSecret(secret string) ClientOption {
+	return func(c *Client) {
+		c.TOTPSecret = secret
+	}
+}
+
+// WithTOTPCode answers the two-factor prompt of the first login with a fixed
+// code. Reconnects reuse the session token, so the code is only needed once.
+func WithTOTPCode(code string) ClientOption {
+	return func(c *Client) {
+		c.TOTPCode = code
+	}
+}
+
+// WithToken authenticates with an existing session token through
+// loginByToken. The password is only used if the token is rejected.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithTokenCache reuses the session token stored in the file by an earlier
+// run and stores the token of every password login there
+func WithTokenCache(path string) ClientOption {
+	return func(c *Client) {
+		c.TokenCachePath = path
+	}
+}
+
+// NewClient creates a new Uptime Kuma API client
+func NewClient(ctx context.Context, baseURL, username, password string, opts ...ClientOption) (*Client, error) {
+	client := &Client{
+		BaseURL:           baseURL,
+		Username:          username,
+		Password:          password,
+		HTTPClient:        &http.Client{Timeout: 30 * time.Second},
+		Timeout:           DefaultTimeout,
+		MaxRetries:        DefaultMaxRetries,
+		RetryBackoff:      DefaultRetryBackoff,
+		connectedCh:       make(chan struct{}),
+		reconnectDelay:    500 * time.Millisecond,
+		maxReconnectDelay: 30 * time.Second,
+		closedCh:          make(chan struct{}),
+		responses:         make(map[int]chan SocketResponse),
+		monitors:          make(map[int]Monitor),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.HTTPClient.Transport = client.httpTransport()
+	client.logCtx = newSocketLogContext(ctx)
+	if client.token == "" && client.TokenCachePath != "" {
+		client.token = client.tokenCache().load(tokenCacheKey(client.BaseURL, client.Username))
+	}
+
+	if client.lazy {
+		return client, nil
+	}
+
+	if err := client.start(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Connect connects and logs in, unless that already happened, and waits for
+// the server version and lists. Clients created with WithLazyConnect do this
+// on their first request; calling it earlier makes ServerVersion and
+// CheckVersion reflect the server before anything is sent to it.
+func (c *Client) Connect(ctx context.Context) error {
+	return c.start(ctx)
+}
+
+// Connected reports whether Connect, or the first request of a lazy client,
+// has completed. It does not block while another goroutine is connecting.
+func (c *Client) Connected() bool {
+	return c.started.Load()
+}
+
+// start connects, authenticates and waits for the lists Uptime Kuma pushes
+// after login, unless that already happened. Concurrent first requests share
+// one connect; after a failure the next request tries again.
+func (c *Client) start(ctx context.Context) error {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+
+	if c.started.Load() {
+		return nil
+	}
+
+	c.mu.RLock()
+	closed := c.closed
+	c.mu.RUnlock()
+	if closed {
+		return errClientClosed
+	}
+
+	// Bound the connect and login unless the context has a deadline of its own
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	// Connect to Socket.IO endpoint and authenticate; the client reconnects
+	// by itself from then on
+	if !c.sessionStarted {
+		if err := c.connect(ctx); err != nil {
+			return err
+		}
+		c.sessionStarted = true
+	}
+
+	// Until the lists arrive the cache is empty, and reading from it would
+	// report every monitor as deleted
+	if err := c.waitInitialLists(ctx); err != nil {
+		return err
+	}
+	c.started.Store(true)
+
+	return nil
+}
+
+// waitInitialLists waits until the first monitor and notification lists have arrived
+func (c *Client) waitInitialLists(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err := c.monitorsVersion.waitAfter(ctx, 0); err != nil {
+		return fmt.Errorf("waiting for monitor list: %w", err)
+	}
+	if err := c.notificationsVersion.waitAfter(ctx, 0); err != nil {
+		return fmt.Errorf("waiting for notification list: %w", err)
+	}
+
+	return nil
+}
+
+// connect dials and authenticates a new session and makes it the current one
+func (c *Client) connect(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	err = c.authenticate(ctx, conn)
+	if err != nil {
+		conn.close()
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		conn.close()
+		return errClientClosed
+	}
+
+	// The session may have dropped while authenticating, before
+	// handleDisconnect could see it as the current one
+	select {
+	case <-conn.done:
+		return fmt.Errorf("failed to connect: %w", errConnectionLost)
+	default:
+	}
+
+	c.conn = conn
+	c.connected = true
+	c.reconnecting = false
+	close(c.connectedCh)
+
+	return nil
+}
+
+// dial establishes WebSocket connection to Uptime Kuma Socket.IO endpoint
+func (c *Client) dial(ctx context.Context) (*connection, error) {
+	wsURL, err := c.socketURL()
+	if err != nil {
+		return nil, err
+	}
+
+	// Connect to WebSocket
+	ws, err := c.transport().Dial(ctx, wsURL, c.Headers.Clone())
+	if err != nil {
+		return nil, fmt.Errorf("websocket connection failed: %w", err)
+	}
+
+	conn := &connection{ws: ws, done: make(chan struct{}), logCtx: c.logCtx}
+
+	if err := c.handshake(ctx, conn); err != nil {
+		conn.close()
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+
+	// Start message handler
+	go c.handleMessages(conn)
+
+	return conn, nil
+}
+
+// dialer returns the WebSocket dialer configured for this client
+func (c *Client) dialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if c.TLSConfig != nil {
+		dialer.TLSClientConfig = c.TLSConfig.Clone()
+	}
+	if c.ProxyURL != nil {
+		dialer.Proxy = http.ProxyURL(c.ProxyURL)
+	}
+	return &dialer
+}
+
+// httpTransport returns the transport for HTTPClient, configured like the
+// WebSocket dialer
+func (c *Client) httpTransport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.TLSConfig != nil {
+		transport.TLSClientConfig = c.TLSConfig.Clone()
+	}
+	if c.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(c.ProxyURL)
+	}
+	if len(c.Headers) == 0 {
+		return transport
+	}
+	return &headerTransport{base: transport, headers: c.Headers}
+}
+
+// headerTransport adds fixed headers to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// socketURL returns the Socket.IO WebSocket endpoint. Uptime Kuma served
+// under a sub-path (https://host/uptime/) has it at /uptime/socket.io/ unless
+// SocketPath says otherwise.
+func (c *Client) socketURL() (string, error) {
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid URL %q: missing host", c.BaseURL)
+	}
+
+	// Convert to WebSocket URL
+	scheme := "ws"
+	if u.Scheme == "https" {
+		scheme = "wss"
+	}
+
+	socketPath := c.SocketPath
+	if socketPath == "" {
+		socketPath = strings.TrimSuffix(u.Path, "/") + "/socket.io/"
+	}
+	if !strings.HasPrefix(socketPath, "/") {
+		socketPath = "/" + socketPath
+	}
+	if !strings.HasSuffix(socketPath, "/") {
+		socketPath += "/"
+	}
+
+	wsURL := url.URL{
+		Scheme:   scheme,
+		Host:     u.Host,
+		Path:     socketPath,
+		RawQuery: "EIO=4&transport=websocket",
+	}
+	return wsURL.String(), nil
+}
+
+// handshake waits for the Engine.IO open packet, joins the default Socket.IO
+// namespace and waits for the server to acknowledge it
+func (c *Client) handshake(ctx context.Context, conn *connection) error {
+	// Bound the blocking reads below by the context
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.ws.SetReadDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.ws.SetReadDeadline(time.Now())
+	})
+
+	err := c.readHandshake(ctx, conn)
+	if !stop() && err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		return err
+	}
+
+	// From here on the read deadline tracks the server's heartbeat
+	conn.ws.SetReadDeadline(time.Time{})
+	conn.resetPingTimeout()
+
+	return nil
+}
+
+// readHandshake performs the packet exchange of handshake
+func (c *Client) readHandshake(ctx context.Context, conn *connection) error {
+	// Engine.IO open packet: 0{"sid":...,"pingInterval":...,"pingTimeout":...}
+	packet, err := conn.readMessage()
+	if err != nil {
+		return fmt.Errorf("failed to read open packet: %w", err)
+	}
+	if !strings.HasPrefix(packet, "0") {
+		return fmt.Errorf("expected Engine.IO open packet, got %q", packet)
+	}
+
+	var open openPacket
+	if err := json.Unmarshal([]byte(packet[1:]), &open); err != nil {
+		return fmt.Errorf("invalid Engine.IO open packet: %w", err)
+	}
+	conn.sid = open.SID
+	conn.pingInterval = time.Duration(open.PingInterval) * time.Millisecond
+	conn.pingTimeout = time.Duration(open.PingTimeout) * time.Millisecond
+
+	// Send Socket.IO connect message for the default namespace
+	if err := conn.writeMessage(ctx, "40"); err != nil {
+		return fmt.Errorf("failed to send connect message: %w", err)
+	}
+
+	for {
+		packet, err := conn.readMessage()
+		if err != nil {
+			return fmt.Errorf("failed to read connect acknowledgement: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(packet, "40"): // Namespace connected
+			return nil
+		case strings.HasPrefix(packet, "44"): // Namespace connect error
+			return fmt.Errorf("server refused connection: %s", packet[2:])
+		case packet == "2": // Ping
+			if err := conn.writeMessage(ctx, "3"); err != nil {
+				return fmt.Errorf("failed to send pong: %w", err)
+			}
+		case strings.HasPrefix(packet, "1"): // Close
+			return fmt.Errorf("server closed the connection during handshake")
+		}
+	}
+}
+
+// authenticate logs in on a new session, reusing the token from an earlier
+// login when there is one so a reconnect does not need the password
+func (c *Client) authenticate(ctx context.Context, conn *connection) error {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token != "" {
+		_, err := c.send(ctx, conn, "loginByToken", token)
+		if err == nil {
+			return nil
+		}
+		// Only a token the server rejected is worth replacing; timeouts and
+		// lost connections say nothing about the token
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) && !errors.Is(err, ErrUnauthorized) {
+			return fmt.Errorf("token login failed: %w", err)
+		}
+		if c.Password == "" {
+			return fmt.Errorf("token rejected and no password to fall back to: %w", err)
+		}
+	}
+
+	return c.login(ctx, conn)
+}
+
+// tokenCache returns the on-disk token cache
+func (c *Client) tokenCache() tokenCache {
+	return tokenCache{path: c.TokenCachePath}
+}
+
+// disconnect closes the WebSocket connection and stops reconnecting
+func (c *Client) disconnect() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.closedCh)
+	}
+	conn := c.conn
+	c.connected = false
+	c.mu.Unlock()
+
+	if conn != nil {
+		conn.close()
+	}
+}
+
+// handleMessages processes incoming WebSocket messages until the session ends
+func (c *Client) handleMessages(conn *connection) {
+	for {
+		message, err := conn.readMessage()
+		if err != nil {
+			c.handleDisconnect(conn)
+			return
+		}
+
+		// Parse Socket.IO message format
+		c.parseMessage(conn, message)
+	}
+}
+
+// handleDisconnect tears down a lost session and, unless the client has been
+// closed, starts reconnecting in the background
+func (c *Client) handleDisconnect(conn *connection) {
+	conn.close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != conn || !c.connected {
+		return
+	}
+	c.connected = false
+	c.connectedCh = make(chan struct{})
+
+	if c.closed || c.reconnecting {
+		return
+	}
+	c.reconnecting = true
+	go c.reconnect()
+}
+
+// reconnect redials and re-authenticates with exponential backoff until it
+// succeeds or the client is closed
+func (c *Client) reconnect() {
+	c.mu.RLock()
+	delay, maxDelay := c.reconnectDelay, c.maxReconnectDelay
+	c.mu.RUnlock()
+
+	for {
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := c.connect(ctx)
+		cancel()
+		if err == nil || errors.Is(err, errClientClosed) {
+			return
+		}
+
+		select {
+		case <-c.closedCh:
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// waitConnected returns the current session, waiting for a reconnect in
+// progress until the context is done
+func (c *Client) waitConnected(ctx context.Context) (*connection, error) {
+	if err := c.start(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		c.mu.RLock()
+		closed, connected, conn, connectedCh := c.closed, c.connected, c.conn, c.connectedCh
+		c.mu.RUnlock()
+
+		if closed {
+			return nil, errClientClosed
+		}
+		if connected {
+			return conn, nil
+		}
+
+		select {
+		case <-connectedCh:
+		case <-c.closedCh:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("not connected: %w", contextError(ctx))
+		}
+	}
+}
+
+// parseMessage parses Engine.IO packets and the Socket.IO messages they carry
+func (c *Client) parseMessage(conn *connection, message string) {
+	if message == "" {
+		return
+	}
+
+	// Engine.IO control packets
+	switch message[0] {
+	case '1': // Close
+		conn.close()
+		return
+	case '2': // Ping - answer with a pong and expect the next ping
+		conn.resetPingTimeout()
+		conn.writeMessage(context.Background(), "3")
+		return
+	}
+
+	if len(message) < 2 {
+		return
+	}
+
+	// Socket.IO message format: [type][optional-id][optional-json]
+	msgType := message[:1]
+	content := message[1:]
+
+	switch msgType {
+	case "4": // Message type
+		if strings.HasPrefix(content, "1") {
+			// Namespace disconnect: the server ended the session
+			conn.close()
+		} else if strings.HasPrefix(content, "3") {
+			// Callback response: 43[ack_id][response_data]
+			content = content[1:] // Remove "3"
+
+			// Extract callback ID and response data
+			var callbackID int
+			var responseData string
+
+			// Find where the JSON starts (after the callback ID)
+			for i := 0; i < len(content); i++ {
+				if content[i] == '[' || content[i] == '{' {
+					if i > 0 {
+						callbackID, _ = strconv.Atoi(content[:i])
+					}
+					responseData = content[i:]
+					break
+				}
+			}
+
+			// Parse response data - acknowledgement arguments are sent as a JSON array
+			var responseArgs []interface{}
+			if err := json.Unmarshal([]byte(responseData), &responseArgs); err == nil {
+				response := SocketResponse{
+					Data: responseArgs,
+				}
+
+				// Send to waiting goroutine
+				c.respMu.RLock()
+				if ch, exists := c.responses[callbackID]; exists {
+					select {
+					case ch <- response:
+					default:
+					}
+				}
+				c.respMu.RUnlock()
+			}
+		} else if strings.HasPrefix(content, "2") {
+			// Event message: 42["event", data...]
+			content = content[1:] // Remove "2"
+			var eventData []json.RawMessage
+			if err := json.Unmarshal([]byte(content), &eventData); err == nil {
+				var event string
+				if len(eventData) >= 2 && json.Unmarshal(eventData[0], &event) == nil {
+					data := eventData[1:]
+
+					// Handle specific events we care about
+					if event == "monitorList" {
+						// Cache the monitor list data
+						if monitors, err := decodeMonitorList(data[0]); err == nil {
+							c.monitorsMu.Lock()
+							c.monitors = monitors
+							c.monitorsMu.Unlock()
+							c.monitorsVersion.bump()
+						}
+					} else if event == "updateMonitorIntoList" {
+						// Uptime Kuma 2.0 only sends the monitors that were added or changed
+						if monitors, err := decodeMonitorList(data[0]); err == nil {
+							c.monitorsMu.Lock()
+							if c.monitors == nil {
+								c.monitors = make(map[int]Monitor)
+							}
+							for id, monitor := range monitors {
+								c.monitors[id] = monitor
+							}
+							c.monitorsMu.Unlock()
+							c.monitorsVersion.bump()
+						}
+					} else if event == "deleteMonitorFromList" {
+						// and the ID of a deleted monitor, whose children the
+						// database moved to the top level
+						var id jsonInt
+						if err := json.Unmarshal(data[0], &id); err == nil {
+							c.monitorsMu.Lock()
+							delete(c.monitors, int(id))
+							for childID, monitor := range c.monitors {
+								if monitor.Parent == int(id) {
+									monitor.Parent = 0
+									c.monitors[childID] = monitor
+								}
+							}
+							c.monitorsMu.Unlock()
+							c.monitorsVersion.bump()
+						}
+					} else if event == "info" {
+						// Uptime Kuma only includes the version once logged in
+						var info struct {
+							Version string `json:"version"`
+						}
+						if err := json.Unmarshal(data[0], &info); err == nil && info.Version != "" {
+							c.mu.Lock()
+							c.serverVersion = info.Version
+							c.mu.Unlock()
+						}
+					} else if event == "notificationList" {
+						// Cache the notification list data
+						var notifList []interface{}
+						if err := json.Unmarshal(data[0], &notifList); err == nil {
+							c.notificationsMu.Lock()
+							c.notificationCache = c.notificationCache[:0] // Clear existing cache
+							for _, item := range notifList {
+								if notifMap, ok := item.(map[string]interface{}); ok {
+									notification := parseNotificationMap(notifMap)
+									c.notificationCache = append(c.notificationCache, notification)
+								}
+							}
+							c.notificationsMu.Unlock()
+							c.notificationsVersion.bump()
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// login authenticates with Uptime Kuma using Socket.IO
+func (c *Client) login(ctx context.Context, conn *connection) error {
+	// Send login event via Socket.IO
+	loginData := map[string]interface{}{
+		"username": c.Username,
+		"password": c.Password,
+		"token":    "",
+	}
+
+	response, err := c.sendLogin(ctx, conn, loginData)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	// Accounts with 2FA answer the first attempt with tokenRequired and
+	// expect the login to be repeated with the current code
+	if required, _ := response["tokenRequired"].(bool); required {
+		code, err := c.totpCode()
+		if err != nil {
+			return err
+		}
+
+		loginData["token"] = code
+		response, err = c.sendLogin(ctx, conn, loginData)
+		if err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+	}
+
+	// Check if login was successful
+	if ok, exists := response["ok"]; exists {
+		if okBool, isBool := ok.(bool); isBool && !okBool {
+			msg := "login failed"
+			if msgStr, exists := response["msg"]; exists {
+				if msgString, isString := msgStr.(string); isString {
+					msg = msgString
+				}
+			}
+			return fmt.Errorf("authentication failed: %s", msg)
+		}
+	}
+
+	// Store authentication information
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if token, exists := response["token"]; exists {
+		if tokenStr, isString := token.(string); isString {
+			c.token = tokenStr
+
+			// The cache only saves a password login next time, so a
+			// failure to write it is not worth failing this one
+			if c.TokenCachePath != "" && tokenStr != "" {
+				_ = c.tokenCache().store(tokenCacheKey(c.BaseURL, c.Username), tokenStr)
+			}
+		}
+	}
+
+	// Store user ID - in Uptime Kuma, user ID is typically 1 for admin
+	// but we should check if it's provided in the response
+	if userID, exists := response["userID"]; exists {
+		if id, ok := userID.(float64); ok {
+			c.userID = int(id)
+		}
+	} else {
+		// Default to user ID 1 for admin if not provided
+		c.userID = 1
+	}
+
+	return nil
+}
+
+// sendLogin sends a login event, retrying while Uptime Kuma's login rate
+// limiter refuses it
+func (c *Client) sendLogin(ctx context.Context, conn *connection, loginData map[string]interface{}) (map[string]interface{}, error) {
+	rateLimited := func(err error) bool {
+		return errors.Is(err, ErrRateLimited)
+	}
+	return c.retry(ctx, "login", rateLimited, func() (map[string]interface{}, error) {
+		return c.send(ctx, conn, "login", loginData)
+	})
+}
+
+// emit sends a Socket.IO event without waiting for response (fire-and-forget)
+func (c *Client) emit(ctx context.Context, event string, data interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	conn, err := c.waitConnected(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Create Socket.IO event message without acknowledgment: 42["event", data]
+	eventData := []interface{}{event, data}
+	eventJSON, err := json.Marshal(eventData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	// Socket.IO message format without acknowledgment: 42[json_array]
+	message := fmt.Sprintf("42%s", string(eventJSON))
+
+	return conn.writeMessage(ctx, message)
+}
+
+// call sends a Socket.IO event and waits up to Timeout for its
+// acknowledgement, within the deadline of the context. Rate limited calls are
+// retried, and so are idempotent events whose connection dropped or whose
+// acknowledgement did not arrive.
+func (c *Client) call(ctx context.Context, event string, args ...interface{}) (map[string]interface{}, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	retryable := func(err error) bool {
+		return ctx.Err() == nil && isRetryable(event, err)
+	}
+
+	return c.retry(ctx, event, retryable, func() (map[string]interface{}, error) {
+		conn, err := c.waitConnected(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if c.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, c.Timeout)
+		}
+		defer cancel()
+
+		return c.send(attemptCtx, conn, event, args...)
+	})
+}
+
+// withTimeout bounds the context by the time a call with all its retries may
+// take, unless it already has a deadline
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.callBudget())
+	}
+	return ctx, func() {}
+}
+
+// send emits an event on the given session and waits for its acknowledgement
+func (c *Client) send(ctx context.Context, conn *connection, event string, args ...interface{}) (map[string]interface{}, error) {
+	// Get next event ID for callback
+	c.mu.Lock()
+	c.eventID++
+	eventID := c.eventID
+	c.mu.Unlock()
+
+	// Create response channel
+	responseCh := make(chan SocketResponse, 1)
+	c.respMu.Lock()
+	c.responses[eventID] = responseCh
+	c.respMu.Unlock()
+
+	// Clean up response channel on exit
+	defer func() {
+		c.respMu.Lock()
+		delete(c.responses, eventID)
+		c.respMu.Unlock()
+		close(responseCh)
+	}()
+
+	// Create Socket.IO call message with callback: 42[ack_id]["event", args..., callback]
+	// The callback parameter is handled by the acknowledgment system
+	eventData := append([]interface{}{event}, args...)
+	eventJSON, err := json.Marshal(eventData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	// Socket.IO message format for binary events with acknowledgments: 42[ack_id][json_array]
+	message := fmt.Sprintf("42%d%s", eventID, string(eventJSON))
+
+	if err := conn.writeMessage(ctx, message); err != nil {
+		select {
+		case <-conn.done:
+			return nil, fmt.Errorf("failed to send %s: %w", event, errConnectionLost)
+		default:
+			return nil, err
+		}
+	}
+
+	// Wait for response until the context is done
+	select {
+	case response := <-responseCh:
+		return parseAck(response)
+
+	case <-conn.done:
+		// The acknowledgement may have been read just before the connection dropped
+		select {
+		case response := <-responseCh:
+			return parseAck(response)
+		default:
+		}
+		return nil, fmt.Errorf("waiting for response to %s: %w", event, errConnectionLost)
+
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w waiting for response to %s: %w", ErrTimeout, event, ctx.Err())
+		}
+		return nil, fmt.Errorf("cancelled waiting for response to %s: %w", event, ctx.Err())
+	}
+}
+
+// totpCode returns the two-factor code to log in with
+func (c *Client) totpCode() (string, error) {
+	if c.TOTPSecret != "" {
+		code, err := totp.Code(c.TOTPSecret, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("authentication failed: %w", err)
+		}
+		return code, nil
+	}
+	if c.TOTPCode != "" {
+		return c.TOTPCode, nil
+	}
+	return "", fmt.Errorf("authentication failed: %w", ErrTOTPRequired)
+}
+
+// parseAck extracts the result object from an acknowledgement, turning
+// ok=false responses into errors
+func parseAck(response SocketResponse) (map[string]interface{}, error) {
+	if response.Error != "" {
+		return nil, fmt.Errorf("server error: %s", response.Error)
+	}
+
+	// Parse response data
+	if len(response.Data) > 0 {
+		if result, ok := response.Data[0].(map[string]interface{}); ok {
+			// Check for "ok" field in response
+			if ok, exists := result["ok"]; exists {
+				if okBool, isBool := ok.(bool); isBool && !okBool {
+					msg := "unknown error"
+					if msgStr, exists := result["msg"]; exists {
+						if msgString, isString := msgStr.(string); isString {
+							msg = msgString
+						}
+					}
+					return nil, &APIError{Msg: msg}
+				}
+			}
+			return result, nil
+		}
+	}
+
+	return map[string]interface{}{}, nil
+}
+
+// writeMessage writes a text frame, bounding the write by the context deadline
+func (cn *connection) writeMessage(ctx context.Context, message string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	cn.writeMu.Lock()
+	defer cn.writeMu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	cn.ws.SetWriteDeadline(deadline)
+	defer cn.ws.SetWriteDeadline(time.Time{})
+
+	if err := cn.ws.WriteFrame(message); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	cn.logFrame("sent", message)
+
+	return nil
+}
+
+// readMessage reads the next frame
+func (cn *connection) readMessage() (string, error) {
+	message, err := cn.ws.ReadFrame()
+	if err != nil {
+		return "", err
+	}
+	cn.logFrame("received", message)
+
+	return message, nil
+}
+
+// makeRequest makes an authenticated request to the Uptime Kuma API
+// GetMonitor retrieves a specific monitor by ID
+func (c *Client) GetMonitor(ctx context.Context, id int) (*Monitor, error) {
+	if err := c.start(ctx); err != nil {
+		return nil, err
+	}
+
+	// Use cached monitor data from the monitorList event
+	c.monitorsMu.RLock()
+	monitor, ok := c.monitors[id]
+	c.monitorsMu.RUnlock()
+
+	if !ok {
+		// Monitor not found in cache
+		return nil, fmt.Errorf("monitor with ID %d: %w", id, ErrNotFound)
+	}
+
+	return copyMonitor(monitor), nil
+}
+
+// RefreshMonitors requests fresh monitor list from the server
+func (c *Client) RefreshMonitors(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	version := c.monitorsVersion.current()
+
+	// Request monitor list via Socket.IO
+	_, err := c.call(ctx, "getMonitorList", nil)
+	if err != nil {
+		return fmt.Errorf("failed to request monitor list: %w", err)
+	}
+
+	// Wait for the monitorList event to update the cache
+	return c.waitMonitorList(ctx, version)
+}
+
+// waitMonitorList waits for a monitorList event, or on Uptime Kuma 2.0 an
+// updateMonitorIntoList or deleteMonitorFromList event, newer than version
+func (c *Client) waitMonitorList(ctx context.Context, version uint64) error {
+	if err := c.monitorsVersion.waitAfter(ctx, version); err != nil {
+		return fmt.Errorf("waiting for monitor list: %w", err)
+	}
+	return nil
+}
+
+// GetMonitors retrieves all monitors
+func (c *Client) GetMonitors(ctx context.Context) ([]Monitor, error) {
+	// Force refresh to get latest data
+	err := c.RefreshMonitors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh monitors: %w", err)
+	}
+
+	// Use cached monitor data from the monitorList event
+	c.monitorsMu.RLock()
+	monitors := make([]Monitor, 0, len(c.monitors))
+	for _, monitor := range c.monitors {
+		monitors = append(monitors, *copyMonitor(monitor))
+	}
+	c.monitorsMu.RUnlock()
+
+	sort.Slice(monitors, func(i, j int) bool { return monitors[i].ID < monitors[j].ID })
+
+	return monitors, nil
+}
+
+// copyMonitor returns a copy of a cached monitor that shares no slices or maps with it
+func copyMonitor(monitor Monitor) *Monitor {
+	monitor.AcceptedStatusCodes = append([]string(nil), monitor.AcceptedStatusCodes...)
+	monitor.Tags = append([]MonitorTag(nil), monitor.Tags...)
+	monitor.NotificationIDList = append([]int(nil), monitor.NotificationIDList...)
+	if monitor.Headers != nil {
+		headers := make(map[string]string, len(monitor.Headers))
+		for name, value := range monitor.Headers {
+			headers[name] = value
+		}
+		monitor.Headers = headers
+	}
+	return &monitor
+}
+
+// CreateMonitor creates a new monitor using Socket.IO
+func (c *Client) CreateMonitor(ctx context.Context, monitor *Monitor) (*Monitor, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	// Set default accepted status codes if not provided
+	acceptedStatusCodes := monitor.AcceptedStatusCodes
+	if len(acceptedStatusCodes) == 0 {
+		acceptedStatusCodes = []string{"200-299"}
+	}
+
+	// Build monitor data in the format expected by Uptime Kuma
+	monitorData := map[string]interface{}{
+		"type":                 monitor.Type,
+		"name":                 monitor.Name,
+		"url":                  monitor.URL,
+		"hostname":             monitor.Hostname,
+		"port":                 monitor.Port,
+		"interval":             monitor.Interval,
+		"timeout":              monitor.Timeout,
+		"retryInterval":        monitor.RetryInterval,
+		"resendInterval":       monitor.ResendInterval,
+		"maxretries":           monitor.MaxRetries,
+		"upsideDown":           monitor.UpsideDown,
+		"maxredirects":         monitor.MaxRedirects,
+		"accepted_statuscodes": acceptedStatusCodes,
+		"method":               monitor.HTTPMethod,
+		"body":                 monitor.Body,
+		"headers":              "",
+		"authMethod":           "",
+		"basic_auth_user":      monitor.BasicAuthUser,
+		"basic_auth_pass":      monitor.BasicAuthPass,
+		"ignoreTls":            monitor.IgnoreTLS,
+		"active":               monitor.Active,
+		"notificationIDList":   map[string]interface{}{}, // Use empty object like existing monitors
+		"httpBodyEncoding":     "json",
+		"expiryNotification":   false,
+		"dns_resolve_server":   "1.1.1.1",
+		"dns_resolve_type":     "A",
+		"proxyId":              nil,
+		"mqttUsername":         "",
+		"mqttPassword":         "",
+		"mqttTopic":            "",
+		"mqttSuccessMessage":   "",
+		"keyword":              "",
+		"invertKeyword":        false,
+		"packetSize":           56,
+		"parent":               nil,
+	}
+
+	// Nest the monitor in a group; null keeps it at the top level
+	if monitor.Parent != 0 {
+		monitorData["parent"] = monitor.Parent
+	}
+
+	// Add notification IDs if any are specified
+	if len(monitor.NotificationIDList) > 0 {
+		notificationIDListMap := make(map[string]interface{})
+		for _, id := range monitor.NotificationIDList {
+			notificationIDListMap[fmt.Sprintf("%d", id)] = true
+		}
+		monitorData["notificationIDList"] = notificationIDListMap
+	} else {
+		monitorData["notificationIDList"] = map[string]interface{}{}
+	}
+
+	// Remove empty fields (but keep important fields)
+	for key, value := range monitorData {
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				delete(monitorData, key)
+			}
+		case int:
+			if v == 0 && key != "port" && key != "interval" && key != "timeout" {
+				delete(monitorData, key)
+			}
+		}
+	}
+
+	if err := c.checkParentOnServer(ctx, 0, monitor.Parent); err != nil {
+		return nil, fmt.Errorf("failed to create monitor: %w", err)
+	}
+
+	// Connect before taking the list version, so the initial list does not
+	// count as the update for the new monitor
+	if err := c.start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create monitor: %w", err)
+	}
+	version := c.monitorsVersion.current()
+
+	// Call the "add" API endpoint and wait for response
+	response, err := c.call(ctx, "add", monitorData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitor: %w", err)
+	}
+
+	// Extract monitor ID from response
+	if monitorID, ok := response["monitorID"].(float64); ok {
+		monitor.ID = int(monitorID)
+	} else if msgData, ok := response["msg"].(map[string]interface{}); ok {
+		// Some responses nest the ID in a msg object
+		if monitorID, ok := msgData["monitorID"].(float64); ok {
+			monitor.ID = int(monitorID)
+		}
+	}
+
+	// Wait for the monitorList (1.23) or updateMonitorIntoList (2.0) event
+	// Uptime Kuma sends after saving, so GetMonitor finds the new monitor
+	if err := c.waitMonitorList(ctx, version); err != nil {
+		return nil, err
+	}
+
+	// If we still don't have an ID, try to find it from the cache as fallback
+	if monitor.ID == 0 {
+		monitors, err := c.GetMonitors(ctx)
+		if err == nil {
+			maxID := 0
+			for _, m := range monitors {
+				if m.Name == monitor.Name && m.URL == monitor.URL && m.ID > maxID {
+					maxID = m.ID
+				}
+			}
+			if maxID > 0 {
+				monitor.ID = maxID
+			}
+		}
+	}
+
+	return monitor, nil
+}
+
+// UpdateMonitor updates an existing monitor
+func (c *Client) UpdateMonitor(ctx context.Context, monitor *Monitor) (*Monitor, error) {
+	// Set default accepted status codes if not provided
+	acceptedStatusCodes := monitor.AcceptedStatusCodes
+	if len(acceptedStatusCodes) == 0 {
+		acceptedStatusCodes = []string{"200-299"}
+	}
+
+	// Build monitor data in the format expected by Uptime Kuma (same as create)
+	monitorData := map[string]interface{}{
+		"id":                   monitor.ID,
+		"type":                 monitor.Type,
+		"name":                 monitor.Name,
+		"url":                  monitor.URL,
+		"hostname":             monitor.Hostname,
+		"port":                 monitor.Port,
+		"interval":             monitor.Interval,
+		"timeout":              monitor.Timeout,
+		"retryInterval":        monitor.RetryInterval,
+		"resendInterval":       monitor.ResendInterval,
+		"maxretries":           monitor.MaxRetries,
+		"upsideDown":           monitor.UpsideDown,
+		"maxredirects":         monitor.MaxRedirects,
+		"accepted_statuscodes": acceptedStatusCodes,
+		"method":               monitor.HTTPMethod,
+		"body":                 monitor.Body,
+		"headers":              "",
+		"authMethod":           "",
+		"basic_auth_user":      monitor.BasicAuthUser,
+		"basic_auth_pass":      monitor.BasicAuthPass,
+		"ignoreTls":            monitor.IgnoreTLS,
+		"active":               monitor.Active,
+		"notificationIDList":   map[string]interface{}{}, // Use empty object like existing monitors
+		"httpBodyEncoding":     "json",
+		"expiryNotification":   false,
+		"dns_resolve_server":   "1.1.1.1",
+		"dns_resolve_type":     "A",
+		"proxyId":              nil,
+		"mqttUsername":         "",
+		"mqttPassword":         "",
+		"mqttTopic":            "",
+		"mqttSuccessMessage":   "",
+		"keyword":              "",
+		"invertKeyword":        false,
+		"packetSize":           56,
+		"parent":               nil,
+	}
+
+	// Nest the monitor in a group; null keeps it at the top level
+	if monitor.Parent != 0 {
+		monitorData["parent"] = monitor.Parent
+	}
+
+	// Add notification IDs if any are specified
+	if len(monitor.NotificationIDList) > 0 {
+		notificationIDListMap := make(map[string]interface{})
+		for _, id := range monitor.NotificationIDList {
+			notificationIDListMap[fmt.Sprintf("%d", id)] = true
+		}
+		monitorData["notificationIDList"] = notificationIDListMap
+	} else {
+		monitorData["notificationIDList"] = map[string]interface{}{}
+	}
+
+	// Remove empty fields
+	for key, value := range monitorData {
+		switch v := value.(type) {
+		case string:
+			if v == "" && key != "id" {
+				delete(monitorData, key)
+			}
+		case int:
+			if v == 0 && key != "port" && key != "interval" && key != "timeout" && key != "id" {
+				delete(monitorData, key)
+			}
+		}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err := c.checkParentOnServer(ctx, monitor.ID, monitor.Parent); err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+
+	version := c.monitorsVersion.current()
+
+	// Call the "editMonitor" API endpoint and wait for confirmation
+	_, err := c.call(ctx, "editMonitor", monitorData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+
+	// Wait for the monitorList event Uptime Kuma sends after saving
+	if err := c.waitMonitorList(ctx, version); err != nil {
+		return nil, err
+	}
+
+	return monitor, nil
+}
+
+// DeleteMonitor deletes a monitor. Deleting a group does not delete the
+// monitors inside it: Uptime Kuma moves them to the top level.
+func (c *Client) DeleteMonitor(ctx context.Context, id int) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	// Uptime Kuma acknowledges deleting an unknown ID, so check the cache to
+	// report monitors that are already gone
+	if _, err := c.GetMonitor(ctx, id); err != nil {
+		return err
+	}
+
+	version := c.monitorsVersion.current()
+
+	// Call "deleteMonitor" API endpoint and wait for confirmation
+	_, err := c.call(ctx, "deleteMonitor", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+
+	// Wait for the monitor to be removed from the cache
+	return c.waitMonitorList(ctx, version)
+}
+
+// RefreshNotifications makes sure the notification cache has been filled.
+// Uptime Kuma has no event to request the list; it pushes notificationList
+// after login and after every change, so the cache is current once the first
+// list has arrived.
+func (c *Client) RefreshNotifications(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err := c.start(ctx); err != nil {
+		return err
+	}
+
+	if err := c.notificationsVersion.waitAfter(ctx, 0); err != nil {
+		return fmt.Errorf("waiting for notification list: %w", err)
+	}
+	return nil
+}
+
+// waitForNotification waits for notificationList events newer than version
+// until one contains a notification accepted by match
+func (c *Client) waitForNotification(ctx context.Context, version uint64, match func(Notification) bool) (*Notification, error) {
+	for {
+		if err := c.notificationsVersion.waitAfter(ctx, version); err != nil {
+			return nil, fmt.Errorf("waiting for notification list: %w", err)
+		}
+		version = c.notificationsVersion.current()
+
+		c.notificationsMu.RLock()
+		for _, notif := range c.notificationCache {
+			if match(notif) {
+				c.notificationsMu.RUnlock()
+				// Return a copy
+				result := notif
+				return &result, nil
+			}
+		}
+		c.notificationsMu.RUnlock()
+	}
+}
+
+// GetNotifications retrieves all notifications from Uptime Kuma
+func (c *Client) GetNotifications(ctx context.Context) ([]Notification, error) {
+	// Force refresh to get latest data
+	err := c.RefreshNotifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh notifications: %w", err)
+	}
+
+	c.notificationsMu.RLock()
+	notifications := make([]Notification, len(c.notificationCache))
+	copy(notifications, c.notificationCache)
+	c.notificationsMu.RUnlock()
+
+	return notifications, nil
+}
+
+// parseNotificationMap converts a map to a Notification struct
+func parseNotificationMap(notifMap map[string]interface{}) Notification {
+	notification := Notification{}
+
+	if id, ok := notifMap["id"].(float64); ok {
+		notification.ID = int(id)
+	}
+	if name, ok := notifMap["name"].(string); ok {
+		notification.Name = name
+	}
+	if active, ok := notifMap["active"].(bool); ok {
+		notification.Active = active
+	}
+	if userID, ok := notifMap["userId"].(float64); ok {
+		notification.UserID = int(userID)
+	}
+	if isDefault, ok := notifMap["isDefault"].(bool); ok {
+		notification.IsDefault = isDefault
+	}
+
+	// Handle config as JSON string
+	if configStr, ok := notifMap["config"].(string); ok {
+		var configMap map[string]interface{}
+		if err := json.Unmarshal([]byte(configStr), &configMap); err == nil {
+			notification.Config = configMap
+
+			// Extract type and other fields from config
+			if notifType, exists := configMap["type"].(string); exists {
+				notification.Type = notifType
+			}
+			if applyExisting, exists := configMap["applyExisting"].(bool); exists {
+				notification.ApplyExisting = applyExisting
+			}
+		}
+	}
+
+	return notification
+}
+
+// GetNotification retrieves a specific notification by ID
+func (c *Client) GetNotification(ctx context.Context, id int) (*Notification, error) {
+	// Get all notifications and find the one with the specified ID
+	notifications, err := c.GetNotifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		if notification.ID == id {
+			// Return a copy to prevent modifications
+			notifCopy := notification
+			if notification.Config != nil {
+				notifCopy.Config = make(map[string]interface{})
+				for k, v := range notification.Config {
+					notifCopy.Config[k] = v
+				}
+			}
+			return &notifCopy, nil
+		}
+	}
+
+	return nil, fmt.Errorf("notification with ID %d: %w", id, ErrNotFound)
+}
+
+// CreateNotification creates a new notification
+func (c *Client) CreateNotification(ctx context.Context, notification *Notification) (*Notification, error) {
+	// Prepare notification data
+	notificationData := map[string]interface{}{
+		"name":          notification.Name,
+		"type":          notification.Type,
+		"isDefault":     notification.IsDefault,
+		"applyExisting": notification.ApplyExisting,
+	}
+
+	// Add configuration parameters
+	if notification.Config != nil {
+		for key, value := range notification.Config {
+			notificationData[key] = value
+		}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	version := c.notificationsVersion.current()
+
+	// addNotification(notification, notificationID) creates the
+	// notification when the ID is null and acknowledges with its new ID
+	response, err := c.call(ctx, "addNotification", notificationData, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	id, ok := response["id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("failed to create notification: no ID in response")
+	}
+
+	// Wait for the notification to appear in a notificationList event
+	created, err := c.waitForNotification(ctx, version, func(notif Notification) bool {
+		return notif.ID == int(id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("notification %d was created but not found in cache: %w", int(id), err)
+	}
+
+	return created, nil
+}
+
+// UpdateNotification updates an existing notification
+func (c *Client) UpdateNotification(ctx context.Context, notification *Notification) (*Notification, error) {
+	// Prepare notification data including the ID for updates
+	notificationData := map[string]interface{}{
+		"id":            notification.ID,
+		"name":          notification.Name,
+		"type":          notification.Type,
+		"isDefault":     notification.IsDefault,
+		"applyExisting": notification.ApplyExisting,
+	}
+
+	// Add configuration parameters
+	if notification.Config != nil {
+		for key, value := range notification.Config {
+			notificationData[key] = value
+		}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	version := c.notificationsVersion.current()
+
+	// Uptime Kuma has no editNotification event; addNotification with an
+	// ID saves over the existing notification
+	_, err := c.call(ctx, "addNotification", notificationData, notification.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	// Wait for the notificationList event sent after saving
+	updated, err := c.waitForNotification(ctx, version, func(notif Notification) bool {
+		return notif.ID == notification.ID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("notification was updated but not found in cache: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteNotification deletes a notification
+func (c *Client) DeleteNotification(ctx context.Context, id int) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	version := c.notificationsVersion.current()
+
+	// Call "deleteNotification" API endpoint and wait for confirmation
+	_, err := c.call(ctx, "deleteNotification", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+
+	// Wait for the notificationList event without the deleted notification
+	if err := c.notificationsVersion.waitAfter(ctx, version); err != nil {
+		return fmt.Errorf("notification was deleted but the list was not refreshed: %w", err)
+	}
+
+	return nil
+}
+
+// TestNotification tests a notification configuration
+func (c *Client) TestNotification(ctx context.Context, notification *Notification) error {
+	// Prepare test data
+	testData := map[string]interface{}{
+		"name":          notification.Name,
+		"type":          notification.Type,
+		"isDefault":     notification.IsDefault,
+		"applyExisting": notification.ApplyExisting,
+	}
+
+	// Add configuration parameters
+	if notification.Config != nil {
+		for key, value := range notification.Config {
+			testData[key] = value
+		}
+	}
+
+	err := c.emit(ctx, "testNotification", testData)
+	if err != nil {
+		return fmt.Errorf("failed to test notification: %w", err)
+	}
+
+	return nil
+}
+
+// Close properly closes the client connection
+func (c *Client) Close() {
+	c.disconnect()
+}
@@ -0,0 +1,979 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/totp"
+)
+
+// newTestClient connects a Client to the fake server and closes it when the test finishes
+func newTestClient(t *testing.T, server *fakekuma.Server) *Client {
+	t.Helper()
+
+	client, err := NewClient(context.Background(), server.URL, server.Username, server.Password)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestNewClient_InvalidCredentials(t *testing.T) {
+	server := fakekuma.NewServer(t)
+
+	client, err := NewClient(context.Background(), server.URL, server.Username, "wrong-password")
+	if err == nil {
+		client.Close()
+		t.Fatal("expected authentication error, got nil")
+	}
+	if !strings.Contains(err.Error(), "Incorrect username or password") {
+		t.Errorf("expected server message in error, got: %s", err)
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got: %s", err)
+	}
+}
+
+func TestClient_MonitorLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	created, err := client.CreateMonitor(ctx, &Monitor{
+		Name:               "example",
+		Type:               "http",
+		URL:                "https://example.com",
+		Interval:           60,
+		Timeout:            30,
+		Active:             true,
+		NotificationIDList: []int{3},
+	})
+	if err != nil {
+		t.Fatalf("CreateMonitor: %s", err)
+	}
+	if created.ID == 0 {
+		t.Fatal("expected monitor ID from add acknowledgement")
+	}
+	if _, ok := server.Monitor(created.ID); !ok {
+		t.Fatalf("monitor %d not stored on server", created.ID)
+	}
+
+	monitor, err := client.GetMonitor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if monitor.Name != "example" || monitor.URL != "https://example.com" || !monitor.Active {
+		t.Errorf("unexpected monitor: %+v", monitor)
+	}
+	if len(monitor.NotificationIDList) != 1 || monitor.NotificationIDList[0] != 3 {
+		t.Errorf("expected notification IDs [3], got %v", monitor.NotificationIDList)
+	}
+
+	created.Name = "renamed"
+	if _, err := client.UpdateMonitor(ctx, created); err != nil {
+		t.Fatalf("UpdateMonitor: %s", err)
+	}
+
+	monitors, err := client.GetMonitors(ctx)
+	if err != nil {
+		t.Fatalf("GetMonitors: %s", err)
+	}
+	if len(monitors) != 1 || monitors[0].Name != "renamed" {
+		t.Errorf("expected one renamed monitor, got %+v", monitors)
+	}
+
+	if err := client.DeleteMonitor(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteMonitor: %s", err)
+	}
+	if ids := server.MonitorIDs(); len(ids) != 0 {
+		t.Errorf("expected no monitors on server, got %v", ids)
+	}
+	if _, err := client.GetMonitor(ctx, created.ID); err == nil {
+		t.Error("expected not found error after delete")
+	}
+}
+
+func TestClient_MonitorLifecycleIncrementalList(t *testing.T) {
+	// Uptime Kuma 2.0 answers changes with updateMonitorIntoList and
+	// deleteMonitorFromList instead of a full monitorList. The delay sends
+	// them well after the acknowledgement.
+	server := fakekuma.NewServer(t, fakekuma.WithVersion("2.0.0-beta.2"), fakekuma.WithListDelay(50*time.Millisecond))
+	client := newTestClient(t, server)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	group, err := client.CreateMonitor(ctx, &Monitor{Name: "group", Type: "group", Interval: 60, Timeout: 48})
+	if err != nil {
+		t.Fatalf("CreateMonitor group: %s", err)
+	}
+	created, err := client.CreateMonitor(ctx, &Monitor{
+		Name:     "example",
+		Type:     "http",
+		URL:      "https://example.com",
+		Interval: 60,
+		Timeout:  30,
+		Parent:   group.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateMonitor: %s", err)
+	}
+	if events := server.Events(); containsEvent(events, "getMonitorList") {
+		t.Errorf("expected the incremental update to fill the cache, got events %v", events)
+	}
+	if _, err := client.GetMonitor(ctx, created.ID); err != nil {
+		t.Fatalf("GetMonitor right after create: %s", err)
+	}
+
+	created.Name = "renamed"
+	if _, err := client.UpdateMonitor(ctx, created); err != nil {
+		t.Fatalf("UpdateMonitor: %s", err)
+	}
+	monitor, err := client.GetMonitor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if monitor.Name != "renamed" || monitor.Parent != group.ID {
+		t.Errorf("unexpected monitor after update: %+v", monitor)
+	}
+
+	// Deleting the group moves its children to the top level
+	if err := client.DeleteMonitor(ctx, group.ID); err != nil {
+		t.Fatalf("DeleteMonitor: %s", err)
+	}
+	if _, err := client.GetMonitor(ctx, group.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found after delete, got: %v", err)
+	}
+	monitor, err = client.GetMonitor(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if monitor.Parent != 0 {
+		t.Errorf("expected child to move to the top level, got parent %d", monitor.Parent)
+	}
+}
+
+func TestClient_NotificationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	created, err := client.CreateNotification(ctx, &Notification{
+		Name:   "slack",
+		Type:   "slack",
+		Config: map[string]interface{}{"slackwebhookURL": "https://hooks.slack.com/services/x"},
+	})
+	if err != nil {
+		t.Fatalf("CreateNotification: %s", err)
+	}
+	if created.ID == 0 {
+		t.Fatal("expected notification ID")
+	}
+
+	notification, err := client.GetNotification(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetNotification: %s", err)
+	}
+	if notification.Type != "slack" || notification.Config["slackwebhookURL"] != "https://hooks.slack.com/services/x" {
+		t.Errorf("unexpected notification: %+v", notification)
+	}
+
+	created.Name = "slack-renamed"
+	updated, err := client.UpdateNotification(ctx, created)
+	if err != nil {
+		t.Fatalf("UpdateNotification: %s", err)
+	}
+	if updated.ID != created.ID {
+		t.Errorf("expected ID %d after update, got %d", created.ID, updated.ID)
+	}
+
+	if err := client.DeleteNotification(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteNotification: %s", err)
+	}
+	if _, err := client.GetNotification(ctx, created.ID); err == nil {
+		t.Error("expected not found error after delete")
+	}
+}
+
+func TestClient_WritesSurfaceServerErrors(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	_, err := client.UpdateMonitor(ctx, &Monitor{ID: 42, Name: "missing", Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30})
+	if err == nil || !strings.Contains(err.Error(), "Permission denied.") {
+		t.Errorf("expected server message from editMonitor, got: %v", err)
+	}
+
+	_, err = client.UpdateNotification(ctx, &Notification{ID: 42, Name: "missing", Type: "webhook"})
+	if err == nil || !strings.Contains(err.Error(), "Cannot find notification") {
+		t.Errorf("expected server message from addNotification, got: %v", err)
+	}
+
+	err = client.DeleteNotification(ctx, 42)
+	if err == nil || !strings.Contains(err.Error(), "Cannot find notification") {
+		t.Errorf("expected server message from deleteNotification, got: %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Msg != "Cannot find notification" {
+		t.Errorf("expected *APIError, got: %#v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from deleteNotification, got: %v", err)
+	}
+
+	if _, err := client.GetMonitor(ctx, 42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from GetMonitor, got: %v", err)
+	}
+	if err := client.DeleteMonitor(ctx, 42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from DeleteMonitor, got: %v", err)
+	}
+
+	// Two notifications with the same name are told apart by the ID in the ack
+	first, err := client.CreateNotification(ctx, &Notification{Name: "duplicate", Type: "webhook"})
+	if err != nil {
+		t.Fatalf("CreateNotification: %s", err)
+	}
+	second, err := client.CreateNotification(ctx, &Notification{Name: "duplicate", Type: "webhook"})
+	if err != nil {
+		t.Fatalf("CreateNotification: %s", err)
+	}
+	if first.ID == second.ID {
+		t.Errorf("expected distinct IDs for notifications with the same name, got %d twice", first.ID)
+	}
+}
+
+func TestClient_WaitsForListEvents(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	id := server.AddMonitor(map[string]interface{}{"name": "existing", "type": "http", "url": "https://example.com"})
+
+	// The fake server pushes the initial lists after acknowledging the login,
+	// so the cache is only filled if NewClient waits for them
+	start := time.Now()
+	client := newTestClient(t, server)
+	if _, err := client.GetMonitor(ctx, id); err != nil {
+		t.Fatalf("GetMonitor right after connecting: %s", err)
+	}
+
+	if err := client.DeleteMonitor(ctx, id); err != nil {
+		t.Fatalf("DeleteMonitor: %s", err)
+	}
+	if _, err := client.GetMonitor(ctx, id); err == nil {
+		t.Error("expected deleted monitor to be gone from the cache")
+	}
+
+	if _, err := client.GetMonitors(ctx); err != nil {
+		t.Fatalf("GetMonitors: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("connect, delete and refresh took %s, expected no fixed delays", elapsed)
+	}
+}
+
+func TestClient_ConcurrentClients(t *testing.T) {
+	ctx := context.Background()
+	const instances, workers = 3, 5
+
+	// One client per Uptime Kuma instance, as with several provider aliases
+	servers := make([]*fakekuma.Server, instances)
+	clients := make([]*Client, instances)
+	for i := range servers {
+		servers[i] = fakekuma.NewServer(t)
+		clients[i] = newTestClient(t, servers[i])
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, instances*workers)
+	for i := range clients {
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(client *Client, w int) {
+				defer wg.Done()
+
+				created, err := client.CreateMonitor(ctx, &Monitor{Name: fmt.Sprintf("monitor-%d", w), Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30})
+				if err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.GetMonitor(ctx, created.ID); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.GetMonitors(ctx); err != nil {
+					errs <- err
+				}
+			}(clients[i], w)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	for i, server := range servers {
+		if ids := server.MonitorIDs(); len(ids) != workers {
+			t.Errorf("server %d: expected %d monitors, got %v", i, workers, ids)
+		}
+	}
+}
+
+func TestClient_SocketURL(t *testing.T) {
+	tests := []struct {
+		baseURL    string
+		socketPath string
+		want       string
+	}{
+		{"http://kuma.example.com:3001", "", "ws://kuma.example.com:3001/socket.io/?EIO=4&transport=websocket"},
+		{"https://ops.example.com/uptime/", "", "wss://ops.example.com/uptime/socket.io/?EIO=4&transport=websocket"},
+		{"https://ops.example.com/uptime", "", "wss://ops.example.com/uptime/socket.io/?EIO=4&transport=websocket"},
+		{"https://ops.example.com/uptime/", "/kuma-ws", "wss://ops.example.com/kuma-ws/?EIO=4&transport=websocket"},
+		{"https://ops.example.com", "uptime/socket.io/", "wss://ops.example.com/uptime/socket.io/?EIO=4&transport=websocket"},
+	}
+
+	for _, tt := range tests {
+		client := &Client{BaseURL: tt.baseURL, SocketPath: tt.socketPath}
+		got, err := client.socketURL()
+		if err != nil {
+			t.Errorf("socketURL(%q, %q): %s", tt.baseURL, tt.socketPath, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("socketURL(%q, %q) = %q, want %q", tt.baseURL, tt.socketPath, got, tt.want)
+		}
+	}
+}
+
+func TestClient_PathPrefix(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithPathPrefix("/uptime"))
+
+	client, err := NewClient(context.Background(), server.URL+"/", server.Username, server.Password)
+	if err != nil {
+		t.Fatalf("NewClient with path prefix: %s", err)
+	}
+	client.Close()
+
+	// An explicit socket path wins over the path of the URL
+	root := strings.TrimSuffix(server.URL, "/uptime")
+	client, err = NewClient(context.Background(), root, server.Username, server.Password, WithSocketPath("/uptime/socket.io/"))
+	if err != nil {
+		t.Fatalf("NewClient with socket path: %s", err)
+	}
+	client.Close()
+}
+
+func TestClient_Headers(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithRequiredHeader("CF-Access-Client-Id", "terraform"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if client, err := NewClient(ctx, server.URL, server.Username, server.Password); err == nil {
+		client.Close()
+		t.Fatal("expected upgrade without the access header to be rejected")
+	}
+
+	headers := http.Header{}
+	headers.Set("CF-Access-Client-Id", "terraform")
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithHeaders(headers))
+	if err != nil {
+		t.Fatalf("NewClient with headers: %s", err)
+	}
+	defer client.Close()
+
+	resp, err := client.HTTPClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("HTTPClient: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusForbidden {
+		t.Error("expected HTTPClient to send the configured headers")
+	}
+}
+
+func TestClient_ProxyURL(t *testing.T) {
+	server := fakekuma.NewServer(t)
+
+	for scheme, start := range map[string]func(t *testing.T, tunnels *atomic.Int32) string{
+		"http":   startHTTPProxy,
+		"socks5": startSOCKS5Proxy,
+	} {
+		t.Run(scheme, func(t *testing.T) {
+			var tunnels atomic.Int32
+			proxyURL, _ := url.Parse(start(t, &tunnels))
+			client, err := NewClient(context.Background(), server.URL, server.Username, server.Password, WithProxyURL(proxyURL))
+			if err != nil {
+				t.Fatalf("NewClient through proxy: %s", err)
+			}
+			client.Close()
+
+			if tunnels.Load() == 0 {
+				t.Error("expected the connection to go through the proxy")
+			}
+		})
+	}
+}
+
+// startHTTPProxy runs a proxy answering CONNECT requests and returns its URL
+func startHTTPProxy(t *testing.T, tunnels *atomic.Int32) string {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		tunnels.Add(1)
+		testTunnel(t, w, r.Host)
+	}))
+	t.Cleanup(proxy.Close)
+
+	return proxy.URL
+}
+
+// startSOCKS5Proxy runs a SOCKS5 proxy without authentication that supports
+// CONNECT only, and returns its URL
+func startSOCKS5Proxy(t *testing.T, tunnels *atomic.Int32) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				target, err := socks5Handshake(conn)
+				if err != nil {
+					t.Errorf("SOCKS5 handshake: %s", err)
+					return
+				}
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0}) // Connection refused
+					return
+				}
+				defer upstream.Close()
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				tunnels.Add(1)
+
+				done := make(chan struct{}, 2)
+				go func() { io.Copy(upstream, conn); done <- struct{}{} }()
+				go func() { io.Copy(conn, upstream); done <- struct{}{} }()
+				<-done
+			}()
+		}
+	}()
+
+	return "socks5://" + listener.Addr().String()
+}
+
+// socks5Handshake accepts the client without authentication and returns the
+// address of its CONNECT request
+func socks5Handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+	if request[1] != 1 {
+		return "", fmt.Errorf("unsupported command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case 1, 4: // IPv4, IPv6
+		ip := make(net.IP, 4)
+		if request[3] == 4 {
+			ip = make(net.IP, 16)
+		}
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = ip.String()
+	case 3: // Domain name
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", fmt.Errorf("unsupported address type %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1]))), nil
+}
+
+// testTunnel answers a CONNECT request by piping the hijacked connection to target
+func testTunnel(t *testing.T, w http.ResponseWriter, target string) {
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+
+	w.WriteHeader(http.StatusOK)
+	downstream, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		t.Errorf("Hijack: %s", err)
+		return
+	}
+	defer downstream.Close()
+
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(upstream, downstream); done <- struct{}{} }()
+	go func() { io.Copy(downstream, upstream); done <- struct{}{} }()
+	<-done
+}
+
+func TestClient_TOTP(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	server := fakekuma.NewServer(t, fakekuma.WithTOTP(secret))
+	ctx := context.Background()
+
+	_, err := NewClient(ctx, server.URL, server.Username, server.Password)
+	if !errors.Is(err, ErrTOTPRequired) {
+		t.Fatalf("expected ErrTOTPRequired without a TOTP setting, got: %v", err)
+	}
+
+	_, err = NewClient(ctx, server.URL, server.Username, server.Password, WithTOTPCode("000000"))
+	if err == nil || !strings.Contains(err.Error(), "authInvalidToken") {
+		t.Fatalf("expected invalid token error for a wrong code, got: %v", err)
+	}
+
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithTOTPSecret(secret))
+	if err != nil {
+		t.Fatalf("NewClient with TOTP secret: %s", err)
+	}
+	client.Close()
+
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatalf("totp.Code: %s", err)
+	}
+	client, err = NewClient(ctx, server.URL, server.Username, server.Password, WithTOTPCode(code))
+	if err != nil {
+		t.Fatalf("NewClient with TOTP code: %s", err)
+	}
+	client.Close()
+}
+
+func TestClient_Token(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	ctx := context.Background()
+
+	client, err := NewClient(ctx, server.URL, "", "", WithToken(fakekuma.DefaultToken))
+	if err != nil {
+		t.Fatalf("NewClient with token: %s", err)
+	}
+	client.Close()
+
+	if events := server.Events(); containsEvent(events, "login") || !containsEvent(events, "loginByToken") {
+		t.Errorf("expected only a token login, got events %v", events)
+	}
+
+	if _, err := NewClient(ctx, server.URL, "", "", WithToken("expired")); err == nil {
+		t.Fatal("expected error for a rejected token without password")
+	}
+}
+
+func TestClient_TokenCache(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	ctx := context.Background()
+	cachePath := filepath.Join(t.TempDir(), "tokens.json")
+
+	// Another instance's stale token must not get in the way
+	other := tokenCache{path: cachePath}
+	if err := other.store(tokenCacheKey(server.URL, server.Username), "stale"); err != nil {
+		t.Fatalf("store: %s", err)
+	}
+	if err := other.store(tokenCacheKey("https://other.example.com", "admin"), "other-token"); err != nil {
+		t.Fatalf("store: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithTokenCache(cachePath))
+		if err != nil {
+			t.Fatalf("NewClient run %d: %s", i, err)
+		}
+		client.Close()
+	}
+
+	logins := 0
+	for _, event := range server.Events() {
+		if event == "login" {
+			logins++
+		}
+	}
+	if logins != 1 {
+		t.Errorf("expected one password login replacing the stale token, got %d in %v", logins, server.Events())
+	}
+
+	if got := other.load(tokenCacheKey(server.URL, server.Username)); got != fakekuma.DefaultToken {
+		t.Errorf("expected cached token %q, got %q", fakekuma.DefaultToken, got)
+	}
+	if got := other.load(tokenCacheKey("https://other.example.com", "admin")); got != "other-token" {
+		t.Errorf("expected other instance's token to be kept, got %q", got)
+	}
+
+	info, err := os.Stat(cachePath)
+	if err != nil {
+		t.Fatalf("Stat: %s", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("expected token cache mode 0600, got %o", mode)
+	}
+}
+
+func TestClient_TokenLoginTimeoutKeepsToken(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	ctx := context.Background()
+	cachePath := filepath.Join(t.TempDir(), "tokens.json")
+
+	cache := tokenCache{path: cachePath}
+	if err := cache.store(tokenCacheKey(server.URL, server.Username), fakekuma.DefaultToken); err != nil {
+		t.Fatalf("store: %s", err)
+	}
+
+	// A token login that gets no answer must not fall back to the password
+	// and replace the cached token
+	server.IgnoreNext("loginByToken", 1)
+	loginCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, err := NewClient(loginCtx, server.URL, server.Username, server.Password, WithTokenCache(cachePath))
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got: %v", err)
+	}
+	if events := server.Events(); containsEvent(events, "login") {
+		t.Errorf("expected no password login, got events %v", events)
+	}
+	if got := cache.load(tokenCacheKey(server.URL, server.Username)); got != fakekuma.DefaultToken {
+		t.Errorf("expected cached token to be kept, got %q", got)
+	}
+}
+
+func TestClient_ServerVersion(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithVersion("2.0.0-beta.2"))
+	client := newTestClient(t, server)
+
+	if got := client.ServerVersion(); got != "2.0.0-beta.2" {
+		t.Errorf("expected version from info event, got %q", got)
+	}
+	if err := client.CheckVersion("SNMP monitors", "2.0.0"); err != nil {
+		t.Errorf("expected 2.0 beta to support 2.0 features: %s", err)
+	}
+	if err := client.CheckVersion("Future feature", "2.1.0"); err == nil {
+		t.Error("expected error for a feature from a newer release")
+	}
+
+	// A server that never reports its version is not locked out
+	unknown := &Client{}
+	if err := unknown.CheckVersion("Future feature", "2.1.0"); err != nil {
+		t.Errorf("expected unknown version to pass, got: %s", err)
+	}
+}
+
+func TestClient_CallHonoursContext(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	// The fake server never acknowledges unknown events, so the call can
+	// only return through the context
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.call(ctx, "unknownEvent", nil)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("call returned after %s, expected it to honour the deadline", elapsed)
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := client.call(cancelled, "unknownEvent", nil); !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected context canceled, got: %v", err)
+	}
+}
+
+func TestClient_DefaultTimeout(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	client.Timeout = 100 * time.Millisecond
+
+	_, err := client.call(context.Background(), "unknownEvent", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded from client timeout, got: %v", err)
+	}
+}
+
+func TestClient_ReconnectsAfterDrop(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	setReconnectDelay(client, 10*time.Millisecond)
+
+	server.DropConnections()
+
+	// The reconnect authenticates with the token from the first login
+	deadline := time.Now().Add(5 * time.Second)
+	for !containsEvent(server.Events(), "loginByToken") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected re-login by token, got events %v", server.Events())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	created, err := client.CreateMonitor(ctx, &Monitor{Name: "after-drop", Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30})
+	if err != nil {
+		t.Fatalf("CreateMonitor after reconnect: %s", err)
+	}
+	if _, ok := server.Monitor(created.ID); !ok {
+		t.Fatalf("monitor %d not stored on server", created.ID)
+	}
+}
+
+func TestClient_RetriesIdempotentCallAfterDrop(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	setReconnectDelay(client, 10*time.Millisecond)
+	client.RetryBackoff = 10 * time.Millisecond
+
+	server.DropNext("getMonitorList")
+	if _, err := client.call(ctx, "getMonitorList", nil); err != nil {
+		t.Fatalf("expected getMonitorList to be retried after reconnect, got: %s", err)
+	}
+
+	server.DropNext("add")
+	_, err := client.CreateMonitor(ctx, &Monitor{Name: "dropped", Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30})
+	if !errors.Is(err, errConnectionLost) {
+		t.Fatalf("expected connection lost for non-idempotent add, got: %v", err)
+	}
+}
+
+func TestClient_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+
+	// Rate limited logins are repeated until the limiter lets one through
+	server.RateLimitNext("login", 2)
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithRetry(2, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("expected rate limited login to be retried, got: %s", err)
+	}
+	defer client.Close()
+
+	// An idempotent call whose acknowledgement is lost is sent again
+	client.Timeout = 200 * time.Millisecond
+	server.IgnoreNext("getMonitorList", 1)
+	if _, err := client.call(ctx, "getMonitorList", nil); err != nil {
+		t.Fatalf("expected getMonitorList to be retried after a lost ack, got: %s", err)
+	}
+
+	// Other calls are only retried when the server refused them
+	server.IgnoreNext("add", 1)
+	_, err = client.CreateMonitor(ctx, &Monitor{Name: "ignored", Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout for non-idempotent add, got: %v", err)
+	}
+	if n := countEvents(server.Events(), "add"); n != 1 {
+		t.Errorf("expected add to be sent once, got %d", n)
+	}
+
+	server.RateLimitNext("add", 1)
+	if _, err := client.CreateMonitor(ctx, &Monitor{Name: "rate-limited", Type: "http", URL: "https://example.com", Interval: 60, Timeout: 30}); err != nil {
+		t.Fatalf("expected rate limited add to be retried, got: %s", err)
+	}
+
+	// Giving up reports the server's answer
+	server.RateLimitNext("login", 3)
+	_, err = NewClient(ctx, server.URL, server.Username, server.Password, WithRetry(2, time.Millisecond))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited after the last retry, got: %v", err)
+	}
+}
+
+func TestClient_LazyConnect(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithLazyConnect())
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	defer client.Close()
+
+	if events := server.Events(); len(events) != 0 {
+		t.Fatalf("expected no events before first use, got %v", events)
+	}
+
+	// Concurrent first requests share one login
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetMonitors(ctx); err != nil {
+				t.Errorf("GetMonitors: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := countEvents(server.Events(), "login"); n != 1 {
+		t.Errorf("expected one login, got %d", n)
+	}
+}
+
+func TestClient_LateInitialLists(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t, fakekuma.WithListDelay(500*time.Millisecond))
+	id := server.AddMonitor(map[string]interface{}{"name": "api", "type": "http", "url": "https://example.com"})
+
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password, WithLazyConnect())
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	defer client.Close()
+
+	// The first use gives up before the lists arrive
+	shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetMonitor(shortCtx, id); !errors.Is(err, ErrTimeout) && !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected timeout waiting for the lists, got: %v", err)
+	}
+
+	// The next one must wait for them too instead of reading the empty cache
+	monitor, err := client.GetMonitor(ctx, id)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if monitor.Name != "api" {
+		t.Errorf("unexpected monitor: %+v", monitor)
+	}
+	if n := countEvents(server.Events(), "login"); n != 1 {
+		t.Errorf("expected one login, got %d", n)
+	}
+}
+
+func TestClient_CloseStopsReconnecting(t *testing.T) {
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+	client.Close()
+
+	if _, err := client.call(context.Background(), "getMonitorList", nil); !errors.Is(err, errClientClosed) {
+		t.Fatalf("expected client closed error, got: %v", err)
+	}
+}
+
+func countEvents(events []string, event string) int {
+	n := 0
+	for _, e := range events {
+		if e == event {
+			n++
+		}
+	}
+	return n
+}
+
+func containsEvent(events []string, event string) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func setReconnectDelay(client *Client, delay time.Duration) {
+	client.mu.Lock()
+	client.reconnectDelay = delay
+	client.mu.Unlock()
+}
+
+func TestClient_AnswersPings(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithPingInterval(20*time.Millisecond, 50*time.Millisecond))
+	client := newTestClient(t, server)
+
+	// The server drops connections that miss a pong, which would show up
+	// as a re-login
+	time.Sleep(300 * time.Millisecond)
+
+	if _, err := client.call(context.Background(), "getMonitorList", nil); err != nil {
+		t.Fatalf("getMonitorList: %s", err)
+	}
+	if events := server.Events(); containsEvent(events, "loginByToken") {
+		t.Errorf("expected connection to stay up, got events %v", events)
+	}
+}
+
+func TestClient_DetectsMissingPings(t *testing.T) {
+	server := fakekuma.NewServer(t, fakekuma.WithPingInterval(20*time.Millisecond, 20*time.Millisecond))
+	client := newTestClient(t, server)
+	setReconnectDelay(client, 10*time.Millisecond)
+
+	server.PausePings()
+
+	// Without pings the client must give up on the connection after
+	// pingInterval + pingTimeout and reconnect
+	deadline := time.Now().Add(5 * time.Second)
+	for !containsEvent(server.Events(), "loginByToken") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected reconnect after missing pings, got events %v", server.Events())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewClient_RequiresSocketIOServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := NewClient(ctx, server.URL, "admin", "admin"); err == nil {
+		t.Fatal("expected error connecting to a server without Socket.IO")
+	}
+}
@@ -0,0 +1,24 @@
+// Package uptimekuma is a client for the Socket.IO API of Uptime Kuma, the
+// API its web UI uses. It logs in, keeps the monitor and notification lists
+// the server pushes in a local cache, and reconnects when the connection
+// drops.
+//
+// A client for scripts and jobs:
+//
+//	client, err := uptimekuma.NewClient(ctx, "https://kuma.example.com", "admin", password)
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	monitors, err := client.GetMonitors(ctx)
+//
+// Options configure TLS, proxies, extra headers, two-factor authentication,
+// session tokens and retries; see the With functions. Errors can be inspected
+// with errors.Is against ErrNotFound, ErrUnauthorized, ErrRateLimited and
+// ErrTimeout, or with errors.As for the *APIError message from the server.
+//
+// Retries are logged through terraform-plugin-log, so they show up in
+// Terraform's log when the context comes from the provider and are discarded
+// otherwise.
+package uptimekuma
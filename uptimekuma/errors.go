@@ -0,0 +1,60 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by Client, for use with errors.Is. Messages Uptime Kuma
+// sends back are reported as *APIError, which matches the sentinel for the
+// condition it describes.
+var (
+	// ErrNotFound means the monitor, notification or other object does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized means the session is not logged in or the credentials were rejected
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means Uptime Kuma refused the request because of too many attempts
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTimeout means the server did not answer before the deadline
+	ErrTimeout = errors.New("timeout")
+)
+
+// APIError is an error message returned by Uptime Kuma in an acknowledgement
+type APIError struct {
+	Msg string
+}
+
+func (e *APIError) Error() string {
+	return "API error: " + e.Msg
+}
+
+// Is maps the messages Uptime Kuma uses for well-known conditions to the
+// matching sentinel error
+func (e *APIError) Is(target error) bool {
+	msg := strings.ToLower(e.Msg)
+
+	switch target {
+	case ErrNotFound:
+		return strings.Contains(msg, "not found") || strings.Contains(msg, "cannot find")
+	case ErrUnauthorized:
+		return strings.Contains(msg, "not logged in") ||
+			strings.Contains(msg, "incorrect username or password") ||
+			strings.Contains(msg, "authinvalidtoken") ||
+			strings.Contains(msg, "invalid token")
+	case ErrRateLimited:
+		return strings.Contains(msg, "too frequently") || strings.Contains(msg, "too many")
+	}
+	return false
+}
+
+// contextError returns the error of a done context, marking an exceeded
+// deadline as ErrTimeout
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrParentCycle means nesting a monitor under a parent would make the
+// monitor its own ancestor
+var ErrParentCycle = errors.New("monitor group cycle")
+
+// CheckParent returns an error if parentID cannot become the parent of the
+// monitor with the given ID (0 for a monitor that does not exist yet): the
+// parent must be a group monitor and must not be the monitor itself or one
+// of its descendants. It uses the cached monitor list and does not connect;
+// like CheckVersion, everything passes until the first list has arrived.
+func (c *Client) CheckParent(id, parentID int) error {
+	if parentID == 0 || c.monitorsVersion.current() == 0 {
+		return nil
+	}
+
+	c.monitorsMu.RLock()
+	defer c.monitorsMu.RUnlock()
+
+	return checkParent(c.monitors, id, parentID)
+}
+
+// checkParentOnServer connects if needed and checks a parent against the
+// current monitor list before it is saved, catching cycles created by other
+// changes since the plan
+func (c *Client) checkParentOnServer(ctx context.Context, id, parentID int) error {
+	if parentID == 0 {
+		return nil
+	}
+	if err := c.start(ctx); err != nil {
+		return err
+	}
+	return c.CheckParent(id, parentID)
+}
+
+// checkParent validates a parent against a monitor list
+func checkParent(monitors map[int]Monitor, id, parentID int) error {
+	if id != 0 && parentID == id {
+		return fmt.Errorf("%w: monitor %d cannot be its own parent", ErrParentCycle, id)
+	}
+
+	parent, ok := monitors[parentID]
+	if !ok {
+		return fmt.Errorf("parent monitor %d: %w", parentID, ErrNotFound)
+	}
+	if parent.Type != "group" {
+		return fmt.Errorf("parent monitor %d is a %s monitor; only group monitors can have children", parentID, parent.Type)
+	}
+
+	// Walk up from the parent; reaching the monitor means it would end up
+	// inside its own subtree
+	chain := []string{strconv.Itoa(id)}
+	seen := make(map[int]bool)
+	for ancestor := parentID; ancestor != 0 && !seen[ancestor]; ancestor = monitors[ancestor].Parent {
+		seen[ancestor] = true
+		chain = append(chain, strconv.Itoa(ancestor))
+		if ancestor == id {
+			return fmt.Errorf("%w: %s", ErrParentCycle, strings.Join(chain, " -> "))
+		}
+	}
+
+	return nil
+}
+
+// ChildMonitors returns the monitors directly inside a group. It refreshes
+// the monitor list first, as the cache may be empty or stale when nothing
+// else has read it.
+func (c *Client) ChildMonitors(ctx context.Context, id int) ([]Monitor, error) {
+	if err := c.RefreshMonitors(ctx); err != nil {
+		return nil, err
+	}
+
+	c.monitorsMu.RLock()
+	defer c.monitorsMu.RUnlock()
+
+	var children []Monitor
+	for _, monitor := range c.monitors {
+		if monitor.Parent == id {
+			children = append(children, *copyMonitor(monitor))
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+
+	return children, nil
+}
@@ -0,0 +1,108 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestCheckParent(t *testing.T) {
+	// 1 and 2 are nested groups, 3 is a monitor in 2, 4 a monitor at the top
+	monitors := map[int]Monitor{
+		1: {ID: 1, Type: "group"},
+		2: {ID: 2, Type: "group", Parent: 1},
+		3: {ID: 3, Type: "http", Parent: 2},
+		4: {ID: 4, Type: "http"},
+	}
+
+	tests := []struct {
+		name      string
+		id        int
+		parentID  int
+		wantErr   error
+		wantChain string
+	}{
+		{"new monitor in group", 0, 2, nil, ""},
+		{"move monitor to other group", 3, 1, nil, ""},
+		{"own parent", 1, 1, ErrParentCycle, ""},
+		{"group into its child", 1, 2, ErrParentCycle, "1 -> 2 -> 1"},
+		{"missing parent", 4, 9, ErrNotFound, ""},
+		{"parent not a group", 4, 3, nil, "only group monitors"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkParent(monitors, tt.id, tt.parentID)
+			if tt.wantErr == nil && tt.wantChain == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got: %s", tt.wantErr, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantChain) {
+				t.Errorf("expected %q in error, got: %s", tt.wantChain, err)
+			}
+		})
+	}
+}
+
+func TestClient_MonitorGroups(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	group, err := client.CreateMonitor(ctx, &Monitor{Name: "payments", Type: "group", Interval: 60, Active: true})
+	if err != nil {
+		t.Fatalf("CreateMonitor group: %s", err)
+	}
+	child, err := client.CreateMonitor(ctx, &Monitor{Name: "api", Type: "http", URL: "https://example.com", Interval: 60, Active: true, Parent: group.ID})
+	if err != nil {
+		t.Fatalf("CreateMonitor child: %s", err)
+	}
+
+	if err := client.RefreshMonitors(ctx); err != nil {
+		t.Fatalf("RefreshMonitors: %s", err)
+	}
+	if got, _ := client.GetMonitor(ctx, child.ID); got.Parent != group.ID {
+		t.Errorf("expected parent %d, got %d", group.ID, got.Parent)
+	}
+	children, err := client.ChildMonitors(ctx, group.ID)
+	if err != nil {
+		t.Fatalf("ChildMonitors: %s", err)
+	}
+	if len(children) != 1 || children[0].ID != child.ID {
+		t.Errorf("expected child %d in group, got %+v", child.ID, children)
+	}
+
+	// Nesting the group under a monitor that is not a group is refused
+	// before anything is sent
+	group.Parent = child.ID
+	before := countEvents(server.Events(), "editMonitor")
+	if _, err := client.UpdateMonitor(ctx, group); err == nil || !strings.Contains(err.Error(), "only group monitors") {
+		t.Errorf("expected parent type error, got: %v", err)
+	}
+	if countEvents(server.Events(), "editMonitor") != before {
+		t.Error("expected no editMonitor for an invalid parent")
+	}
+
+	// Deleting the group moves its child to the top level
+	if err := client.DeleteMonitor(ctx, group.ID); err != nil {
+		t.Fatalf("DeleteMonitor: %s", err)
+	}
+	got, err := client.GetMonitor(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetMonitor: %s", err)
+	}
+	if got.Parent != 0 {
+		t.Errorf("expected child at the top level, got parent %d", got.Parent)
+	}
+}
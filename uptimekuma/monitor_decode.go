@@ -0,0 +1,296 @@
+package uptimekuma
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// monitorPayload mirrors Monitor.toJSON() on the Uptime Kuma server. Field
+// types are lenient because they differ between versions and databases:
+// 1.23 on SQLite sends some booleans as 0/1, 2.0 may send fractional
+// timeouts, and nullable columns arrive as null.
+type monitorPayload struct {
+	ID                  jsonInt     `json:"id"`
+	Name                jsonString  `json:"name"`
+	Description         jsonString  `json:"description"`
+	Type                jsonString  `json:"type"`
+	Parent              jsonInt     `json:"parent"`
+	URL                 jsonString  `json:"url"`
+	Method              jsonString  `json:"method"`
+	Hostname            jsonString  `json:"hostname"`
+	Port                jsonInt     `json:"port"`
+	Interval            jsonInt     `json:"interval"`
+	Timeout             jsonInt     `json:"timeout"`
+	RetryInterval       jsonInt     `json:"retryInterval"`
+	ResendInterval      jsonInt     `json:"resendInterval"`
+	MaxRetries          jsonInt     `json:"maxretries"`
+	MaxRedirects        jsonInt     `json:"maxredirects"`
+	Active              jsonBool    `json:"active"`
+	UpsideDown          jsonBool    `json:"upsideDown"`
+	IgnoreTLS           jsonBool    `json:"ignoreTls"`
+	FollowRedirect      jsonBool    `json:"follow_redirect"`
+	Keyword             jsonString  `json:"keyword"`
+	InvertKeyword       jsonBool    `json:"invertKeyword"`
+	ExpiryNotification  jsonBool    `json:"expiryNotification"`
+	PacketSize          jsonInt     `json:"packetSize"`
+	AcceptedStatusCodes jsonStrings `json:"accepted_statuscodes"`
+	DNSResolveType      jsonString  `json:"dns_resolve_type"`
+	DNSResolveServer    jsonString  `json:"dns_resolve_server"`
+	HTTPBodyEncoding    jsonString  `json:"httpBodyEncoding"`
+	AuthMethod          jsonString  `json:"authMethod"`
+	Body                jsonString  `json:"body"`
+	Headers             jsonHeaders `json:"headers"`
+	BasicAuthUser       jsonString  `json:"basic_auth_user"`
+	BasicAuthPass       jsonString  `json:"basic_auth_pass"`
+	PushToken           jsonString  `json:"pushToken"`
+	NotificationIDList  jsonIDSet   `json:"notificationIDList"`
+	NotificationIDs     jsonIDSet   `json:"notification_id_list"` // Older spelling
+	Tags                jsonTags    `json:"tags"`
+}
+
+// decodeMonitor converts one monitor object as sent by Uptime Kuma into a
+// Monitor. Fields with unexpected types are left at their zero value rather
+// than failing the whole monitor.
+func decodeMonitor(data []byte) (Monitor, error) {
+	var payload monitorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return Monitor{}, fmt.Errorf("decoding monitor: %w", err)
+	}
+	if payload.ID == 0 {
+		return Monitor{}, fmt.Errorf("decoding monitor: missing id")
+	}
+
+	notificationIDs := payload.NotificationIDList
+	if len(notificationIDs) == 0 {
+		notificationIDs = payload.NotificationIDs
+	}
+
+	return Monitor{
+		ID:                  int(payload.ID),
+		Name:                string(payload.Name),
+		Description:         string(payload.Description),
+		Type:                string(payload.Type),
+		Parent:              int(payload.Parent),
+		URL:                 string(payload.URL),
+		Hostname:            string(payload.Hostname),
+		Port:                int(payload.Port),
+		Interval:            int(payload.Interval),
+		Timeout:             int(payload.Timeout),
+		RetryInterval:       int(payload.RetryInterval),
+		ResendInterval:      int(payload.ResendInterval),
+		MaxRetries:          int(payload.MaxRetries),
+		UpsideDown:          bool(payload.UpsideDown),
+		MaxRedirects:        int(payload.MaxRedirects),
+		AcceptedStatusCodes: []string(payload.AcceptedStatusCodes),
+		FollowRedirect:      bool(payload.FollowRedirect),
+		Tags:                []MonitorTag(payload.Tags),
+		NotificationIDList:  []int(notificationIDs),
+		Active:              bool(payload.Active),
+		IgnoreTLS:           bool(payload.IgnoreTLS),
+		HTTPMethod:          string(payload.Method),
+		Body:                string(payload.Body),
+		Headers:             map[string]string(payload.Headers),
+		BasicAuthUser:       string(payload.BasicAuthUser),
+		BasicAuthPass:       string(payload.BasicAuthPass),
+		Keyword:             string(payload.Keyword),
+		InvertKeyword:       bool(payload.InvertKeyword),
+		ExpiryNotification:  bool(payload.ExpiryNotification),
+		PacketSize:          int(payload.PacketSize),
+		DNSResolveType:      string(payload.DNSResolveType),
+		DNSResolveServer:    string(payload.DNSResolveServer),
+		HTTPBodyEncoding:    string(payload.HTTPBodyEncoding),
+		AuthMethod:          string(payload.AuthMethod),
+		PushToken:           string(payload.PushToken),
+	}, nil
+}
+
+// decodeMonitorList converts the payload of a monitorList event, an object
+// keyed by monitor ID, into monitors by ID. Monitors that cannot be decoded
+// are skipped.
+func decodeMonitorList(data []byte) (map[int]Monitor, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decoding monitor list: %w", err)
+	}
+
+	monitors := make(map[int]Monitor, len(raw))
+	for _, item := range raw {
+		monitor, err := decodeMonitor(item)
+		if err != nil {
+			continue
+		}
+		monitors[monitor.ID] = monitor
+	}
+
+	return monitors, nil
+}
+
+// jsonString accepts a string or null
+type jsonString string
+
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	var v string
+	if json.Unmarshal(data, &v) == nil {
+		*s = jsonString(v)
+	}
+	return nil
+}
+
+// jsonInt accepts a number, a numeric string or null. Fractions are rounded.
+type jsonInt int
+
+func (i *jsonInt) UnmarshalJSON(data []byte) error {
+	var f float64
+	if json.Unmarshal(data, &f) == nil {
+		*i = jsonInt(math.Round(f))
+		return nil
+	}
+
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			*i = jsonInt(math.Round(f))
+		}
+	}
+	return nil
+}
+
+// jsonBool accepts a boolean, 0/1 or their string forms, or null
+type jsonBool bool
+
+func (b *jsonBool) UnmarshalJSON(data []byte) error {
+	var v bool
+	if json.Unmarshal(data, &v) == nil {
+		*b = jsonBool(v)
+		return nil
+	}
+
+	var f float64
+	if json.Unmarshal(data, &f) == nil {
+		*b = f != 0
+		return nil
+	}
+
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*b = jsonBool(s == "1" || strings.EqualFold(s, "true"))
+	}
+	return nil
+}
+
+// jsonStrings accepts a list of strings or null
+type jsonStrings []string
+
+func (s *jsonStrings) UnmarshalJSON(data []byte) error {
+	var items []jsonString
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, string(item))
+	}
+	*s = values
+	return nil
+}
+
+// jsonIDSet accepts {"1": true, "2": false} as well as [1, "2"] and yields
+// the enabled IDs in ascending order
+type jsonIDSet []int
+
+func (s *jsonIDSet) UnmarshalJSON(data []byte) error {
+	var ids []int
+
+	var set map[string]jsonBool
+	var list []jsonInt
+	switch {
+	case json.Unmarshal(data, &set) == nil:
+		for key, enabled := range set {
+			if id, err := strconv.Atoi(key); err == nil && bool(enabled) {
+				ids = append(ids, id)
+			}
+		}
+	case json.Unmarshal(data, &list) == nil:
+		for _, id := range list {
+			if id != 0 {
+				ids = append(ids, int(id))
+			}
+		}
+	}
+
+	sort.Ints(ids)
+	*s = ids
+	return nil
+}
+
+// jsonTags accepts the tag objects of a monitor, or plain names from older
+// payloads, and yields the assignments
+type jsonTags []MonitorTag
+
+func (t *jsonTags) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+
+	var tags []MonitorTag
+	for _, item := range items {
+		var tag struct {
+			TagID jsonInt    `json:"tag_id"`
+			Name  jsonString `json:"name"`
+			Color jsonString `json:"color"`
+			Value jsonString `json:"value"`
+		}
+		var name string
+		if json.Unmarshal(item, &name) == nil {
+			tags = append(tags, MonitorTag{Name: name})
+		} else if json.Unmarshal(item, &tag) == nil && (tag.TagID != 0 || tag.Name != "") {
+			tags = append(tags, MonitorTag{
+				TagID: int(tag.TagID),
+				Name:  string(tag.Name),
+				Color: string(tag.Color),
+				Value: string(tag.Value),
+			})
+		}
+	}
+
+	*t = tags
+	return nil
+}
+
+// jsonHeaders accepts the headers column, which Uptime Kuma stores as a JSON
+// document in a string, or an object
+type jsonHeaders map[string]string
+
+func (h *jsonHeaders) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		data = []byte(s)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	var headers map[string]interface{}
+	if json.Unmarshal(data, &headers) != nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if str, ok := value.(string); ok {
+			result[name] = str
+		} else {
+			result[name] = fmt.Sprint(value)
+		}
+	}
+	*h = result
+	return nil
+}
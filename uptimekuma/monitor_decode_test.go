@@ -0,0 +1,174 @@
+package uptimekuma
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// The monitor lists in testdata follow Monitor.toJSON in the 1.23 and 2.0
+// server sources. They are not yet captures from running instances; replace
+// them with real monitorList payloads when re-recording.
+func TestDecodeMonitorList(t *testing.T) {
+	tests := []struct {
+		file string
+		want map[int]Monitor
+	}{
+		{
+			file: "monitor_list_1.23.json",
+			want: map[int]Monitor{
+				1: {
+					ID:                  1,
+					Name:                "example.com",
+					Type:                "http",
+					URL:                 "https://example.com",
+					Interval:            60,
+					Timeout:             48,
+					RetryInterval:       60,
+					MaxRetries:          2,
+					MaxRedirects:        10,
+					AcceptedStatusCodes: []string{"200-299"},
+					Tags: []MonitorTag{
+						{TagID: 2, Name: "production", Color: "#059669"},
+						{TagID: 5, Name: "region", Color: "#2563EB", Value: "eu"},
+					},
+					NotificationIDList: []int{1, 3},
+					Active:             true,
+					HTTPMethod:         "GET",
+					Headers:            map[string]string{"X-Env": "prod"},
+					BasicAuthUser:      "monitor",
+					BasicAuthPass:      "s3cret",
+					ExpiryNotification: true,
+					PacketSize:         56,
+					DNSResolveType:     "A",
+					DNSResolveServer:   "1.1.1.1",
+					HTTPBodyEncoding:   "json",
+					AuthMethod:         "basic",
+				},
+				2: {
+					ID:                  2,
+					Name:                "db",
+					Description:         "Primary database",
+					Type:                "port",
+					URL:                 "https://",
+					Hostname:            "db.internal",
+					Port:                5432,
+					Interval:            20,
+					Timeout:             16,
+					RetryInterval:       20,
+					UpsideDown:          true,
+					MaxRedirects:        10,
+					AcceptedStatusCodes: []string{"200-299"},
+					Tags:                nil,
+					Active:              false,
+					HTTPMethod:          "GET",
+					PacketSize:          56,
+					DNSResolveType:      "A",
+					DNSResolveServer:    "1.1.1.1",
+					HTTPBodyEncoding:    "json",
+				},
+			},
+		},
+		{
+			file: "monitor_list_2.0.json",
+			want: map[int]Monitor{
+				7: {
+					ID:                  7,
+					Name:                "api",
+					Type:                "keyword",
+					Parent:              6,
+					URL:                 "https://api.example.com/health",
+					Interval:            60,
+					Timeout:             48,
+					RetryInterval:       30,
+					ResendInterval:      5,
+					MaxRetries:          3,
+					AcceptedStatusCodes: []string{"200-299", "301"},
+					Tags:                []MonitorTag{{TagID: 2, Name: "production", Color: "#059669"}},
+					NotificationIDList:  []int{2},
+					Active:              true,
+					IgnoreTLS:           true,
+					HTTPMethod:          "POST",
+					Body:                `{"ping": true}`,
+					Keyword:             "ok",
+					InvertKeyword:       true,
+					PacketSize:          56,
+					DNSResolveType:      "A",
+					DNSResolveServer:    "1.1.1.1",
+					HTTPBodyEncoding:    "json",
+				},
+				8: {
+					ID:                  8,
+					Name:                "heartbeat",
+					Type:                "push",
+					URL:                 "https://",
+					Interval:            60,
+					Timeout:             48,
+					RetryInterval:       60,
+					MaxRedirects:        10,
+					AcceptedStatusCodes: []string{"200-299"},
+					HTTPMethod:          "GET",
+					PacketSize:          56,
+					PushToken:           "Xb3jq8Kd2L",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("ReadFile: %s", err)
+			}
+
+			got, err := decodeMonitorList(data)
+			if err != nil {
+				t.Fatalf("decodeMonitorList: %s", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d monitors, got %d", len(tt.want), len(got))
+			}
+			for id, want := range tt.want {
+				if !reflect.DeepEqual(got[id], want) {
+					t.Errorf("monitor %d:\n got  %+v\n want %+v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeMonitor_FieldFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    Monitor
+	}{
+		{"numeric strings", `{"id": "5", "interval": "60", "port": "8080"}`, Monitor{ID: 5, Interval: 60, Port: 8080}},
+		{"fractional numbers", `{"id": 5, "timeout": 38.4, "interval": 60.0}`, Monitor{ID: 5, Timeout: 38, Interval: 60}},
+		{"numeric booleans", `{"id": 5, "active": 1, "ignoreTls": 1, "upsideDown": 0}`, Monitor{ID: 5, Active: true, IgnoreTLS: true}},
+		{"boolean strings", `{"id": 5, "active": "1", "upsideDown": "true", "ignoreTls": "0"}`, Monitor{ID: 5, Active: true, UpsideDown: true}},
+		{"notification ID array", `{"id": 5, "notificationIDList": ["3", 1]}`, Monitor{ID: 5, NotificationIDList: []int{1, 3}}},
+		{"legacy notification key", `{"id": 5, "notification_id_list": {"2": true}}`, Monitor{ID: 5, NotificationIDList: []int{2}}},
+		{"tag names", `{"id": 5, "tags": ["a", "b"]}`, Monitor{ID: 5, Tags: []MonitorTag{{Name: "a"}, {Name: "b"}}}},
+		{"headers object", `{"id": 5, "headers": {"X-Count": 2}}`, Monitor{ID: 5, Headers: map[string]string{"X-Count": "2"}}},
+		{"unexpected types", `{"id": 5, "name": 12, "interval": {}, "accepted_statuscodes": "200", "headers": "not json"}`, Monitor{ID: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeMonitor([]byte(tt.payload))
+			if err != nil {
+				t.Fatalf("decodeMonitor: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := decodeMonitor([]byte(`{"name": "no id"}`)); err == nil {
+		t.Error("expected error for a monitor without ID")
+	}
+}
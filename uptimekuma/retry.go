@@ -0,0 +1,98 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMaxRetries is how often a failed request is repeated when the
+	// failure is worth retrying
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff is the delay before the first retry; it doubles
+	// with every further retry
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+// maxRetryBackoff caps the delay between two retries
+const maxRetryBackoff = 30 * time.Second
+
+// WithRetry sets how often requests that failed because of rate limiting or a
+// lost acknowledgement are repeated, and the delay before the first retry
+func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+		c.RetryBackoff = backoff
+	}
+}
+
+// retry runs attempt until it succeeds, fails with an error retryable
+// rejects, or MaxRetries retries have failed too. Retries wait for a jittered,
+// exponentially growing delay.
+func (c *Client) retry(ctx context.Context, event string, retryable func(error) bool, attempt func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	for n := 1; ; n++ {
+		result, err := attempt()
+		if err == nil || n > c.MaxRetries || !retryable(err) {
+			return result, err
+		}
+
+		delay := jitter(c.retryDelay(n))
+		tflog.Warn(ctx, "Retrying Uptime Kuma request", map[string]interface{}{
+			"event":       event,
+			"retry":       n,
+			"max_retries": c.MaxRetries,
+			"delay":       delay.String(),
+			"error":       err.Error(),
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-c.closedCh:
+			return nil, errClientClosed
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
+
+// retryDelay returns the longest delay before the nth retry
+func (c *Client) retryDelay(n int) time.Duration {
+	delay := c.RetryBackoff
+	for i := 1; i < n && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// jitter picks a delay between half and all of delay, so clients that failed
+// together do not retry in lockstep
+func jitter(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// callBudget is how long a call may take with all its retries
+func (c *Client) callBudget() time.Duration {
+	budget := c.Timeout
+	for n := 1; n <= c.MaxRetries; n++ {
+		budget += c.retryDelay(n) + c.Timeout
+	}
+	return budget
+}
+
+// isRetryable reports whether a failed call of the event may be repeated.
+// Rate limited requests were never carried out; a lost connection or
+// acknowledgement leaves that open, so only idempotent events are repeated.
+func isRetryable(event string, err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	return idempotentEvents[event] && (errors.Is(err, errConnectionLost) || errors.Is(err, ErrTimeout))
+}
@@ -0,0 +1,140 @@
+package uptimekuma
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// SocketLogSubsystem is the tflog subsystem every Socket.IO frame sent or
+// received is logged to at TRACE level. Its level can be set separately
+// through TF_LOG_PROVIDER_UPTIMEKUMA_SOCKET.
+const SocketLogSubsystem = "uptimekuma_socket"
+
+// redacted replaces secrets in logged frames
+const redacted = "***"
+
+// sensitiveKey matches the keys whose values are masked in logged frames:
+// passwords and tokens of logins and monitors, and the credentials, keys and
+// webhook URLs of notification configs
+var sensitiveKey = regexp.MustCompile(`(?i)(pass|passwd|password|secret\w*|token|key|webhook\w*|authorization|cookie)$`)
+
+// newSocketLogContext returns a context for logging frames, detached from the
+// cancellation of ctx as it outlives the request that created the client
+func newSocketLogContext(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(context.WithoutCancel(ctx), SocketLogSubsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_UPTIMEKUMA_SOCKET"))
+}
+
+// logFrame logs a frame with its event name and ack ID, secrets masked
+func (cn *connection) logFrame(direction, frame string) {
+	if cn.logCtx == nil {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"direction": direction,
+		"frame":     redactFrame(frame),
+	}
+	event, ackID := frameEvent(frame)
+	if event != "" {
+		fields["event"] = event
+	}
+	if ackID != "" {
+		fields["ack_id"] = ackID
+	}
+
+	tflog.SubsystemTrace(cn.logCtx, SocketLogSubsystem, "Socket.IO frame "+direction, fields)
+}
+
+// splitFrame splits a frame into its packet type and ack ID prefix, such as
+// "421", and its JSON payload
+func splitFrame(frame string) (string, string) {
+	i := strings.IndexAny(frame, "[{\"")
+	if i < 0 {
+		return frame, ""
+	}
+	return frame[:i], frame[i:]
+}
+
+// frameEvent returns the event name of a Socket.IO event and the ack ID of an
+// event or acknowledgement
+func frameEvent(frame string) (string, string) {
+	prefix, payload := splitFrame(frame)
+	if len(prefix) < 2 || prefix[0] != '4' {
+		return "", ""
+	}
+
+	ackID := prefix[2:]
+	if _, err := strconv.Atoi(ackID); err != nil {
+		ackID = ""
+	}
+
+	var event string
+	if prefix[1] == '2' {
+		var args []json.RawMessage
+		if json.Unmarshal([]byte(payload), &args) == nil && len(args) > 0 {
+			json.Unmarshal(args[0], &event)
+		}
+	}
+
+	return event, ackID
+}
+
+// redactFrame masks the values of sensitive keys anywhere in the payload of
+// a frame, including in JSON documents nested in strings such as the config
+// of a notification. Payloads that cannot be parsed are dropped entirely.
+func redactFrame(frame string) string {
+	prefix, payload := splitFrame(frame)
+	if payload == "" {
+		return frame
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return prefix + redacted
+	}
+
+	// loginByToken passes the token as a bare argument
+	if args, ok := value.([]interface{}); ok && len(args) > 1 && args[0] == "loginByToken" {
+		args[1] = redacted
+	}
+
+	data, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return prefix + redacted
+	}
+	return prefix + string(data)
+}
+
+// redactValue masks the sensitive keys of maps within value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveKey.MatchString(key) && item != nil && item != "" {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(v), "{") {
+			var nested interface{}
+			if json.Unmarshal([]byte(v), &nested) == nil {
+				if data, err := json.Marshal(redactValue(nested)); err == nil {
+					return string(data)
+				}
+			}
+		}
+	}
+	return value
+}
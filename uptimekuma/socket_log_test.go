@@ -0,0 +1,124 @@
+package uptimekuma
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestRedactFrame(t *testing.T) {
+	tests := []struct {
+		name, frame, want string
+	}{
+		{"login", `421["login",{"password":"hunter2","token":"","username":"admin"}]`, `421["login",{"password":"***","token":"","username":"admin"}]`},
+		{"login ack", `431[{"ok":true,"token":"eyJhbGciOi"}]`, `431[{"ok":true,"token":"***"}]`},
+		{"login by token", `422["loginByToken","eyJhbGciOi"]`, `422["loginByToken","***"]`},
+		{"monitor", `423["add",{"basic_auth_pass":"s3cret","basic_auth_user":"monitor","keyword":"ok"}]`, `423["add",{"basic_auth_pass":"***","basic_auth_user":"monitor","keyword":"ok"}]`},
+		{"notification config", `42["notificationList",[{"config":"{\"discordWebhookUrl\":\"https://discord.com/api/webhooks/1/x\",\"type\":\"discord\"}","id":1}]]`, `42["notificationList",[{"config":"{\"discordWebhookUrl\":\"***\",\"type\":\"discord\"}","id":1}]]`},
+		{"notification keys", `424["addNotification",{"pagerdutyIntegrationKey":"abc","smtpPassword":"pw","telegramBotToken":"t","name":"n"},null]`, `424["addNotification",{"name":"n","pagerdutyIntegrationKey":"***","smtpPassword":"***","telegramBotToken":"***"},null]`},
+		{"ping", `2`, `2`},
+		{"garbled", `42["login",{"password":"hunt`, `42***`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactFrame(tt.frame); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameEvent(t *testing.T) {
+	tests := []struct {
+		frame, event, ackID string
+	}{
+		{`421["login",{}]`, "login", "1"},
+		{`42["monitorList",{}]`, "monitorList", ""},
+		{`4317[{"ok":true}]`, "", "17"},
+		{`40`, "", ""},
+		{`3`, "", ""},
+	}
+
+	for _, tt := range tests {
+		event, ackID := frameEvent(tt.frame)
+		if event != tt.event || ackID != tt.ackID {
+			t.Errorf("frameEvent(%s) = %q, %q, want %q, %q", tt.frame, event, ackID, tt.event, tt.ackID)
+		}
+	}
+}
+
+func TestClient_LogsFrames(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_UPTIMEKUMA_SOCKET", "TRACE")
+
+	var output syncBuffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	server := fakekuma.NewServer(t)
+
+	client, err := NewClient(ctx, server.URL, server.Username, server.Password)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	defer client.Close()
+
+	_, err = client.CreateNotification(ctx, &Notification{
+		Name:   "discord",
+		Type:   "discord",
+		Config: map[string]interface{}{"discordWebhookUrl": "https://discord.com/api/webhooks/1/secret-path"},
+	})
+	if err != nil {
+		t.Fatalf("CreateNotification: %s", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(strings.NewReader(output.String()))
+	if err != nil {
+		t.Fatalf("MultilineJSONDecode: %s", err)
+	}
+
+	var sentLogin, sawAck bool
+	for _, entry := range entries {
+		if entry["@module"] != "provider."+SocketLogSubsystem || entry["@level"] != "trace" {
+			continue
+		}
+		if entry["direction"] == "sent" && entry["event"] == "login" && entry["ack_id"] != nil {
+			sentLogin = true
+		}
+		if entry["direction"] == "received" && entry["event"] == nil && entry["ack_id"] != nil {
+			sawAck = true
+		}
+	}
+	if !sentLogin || !sawAck {
+		t.Errorf("expected sent login and received ack frames in the log, got:\n%s", output.String())
+	}
+
+	for _, secret := range []string{server.Password, fakekuma.DefaultToken, "secret-path"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("log contains secret %q", secret)
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the logger
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
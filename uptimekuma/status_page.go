@@ -0,0 +1,272 @@
+package uptimekuma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StatusPage is an Uptime Kuma status page. Its slug is the address of the
+// page (/status/<slug>) and identifies it in every call.
+type StatusPage struct {
+	ID                    int      `json:"id,omitempty"`
+	Slug                  string   `json:"slug"`
+	Title                 string   `json:"title"`
+	Description           string   `json:"description"`
+	Icon                  string   `json:"icon"` // URL, or a PNG data URL to upload
+	Theme                 string   `json:"theme"`
+	Published             bool     `json:"published"`
+	ShowTags              bool     `json:"showTags"`
+	DomainNameList        []string `json:"domainNameList"`
+	CustomCSS             string   `json:"customCSS"`
+	FooterText            string   `json:"footerText"`
+	ShowPoweredBy         bool     `json:"showPoweredBy"`
+	ShowCertificateExpiry bool     `json:"showCertificateExpiry"`
+	GoogleAnalyticsID     string   `json:"googleAnalyticsId"`
+}
+
+// PublicGroup is a group of monitors shown on a status page
+type PublicGroup struct {
+	ID       int             `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Monitors []PublicMonitor `json:"monitorList"`
+}
+
+// PublicMonitor is a monitor shown in a public group
+type PublicMonitor struct {
+	ID      int  `json:"id"`
+	SendURL bool `json:"sendUrl"` // Show the monitored URL as a link
+}
+
+// statusPagePayload mirrors StatusPage.toJSON() on the Uptime Kuma server
+type statusPagePayload struct {
+	ID                    jsonInt     `json:"id"`
+	Slug                  jsonString  `json:"slug"`
+	Title                 jsonString  `json:"title"`
+	Description           jsonString  `json:"description"`
+	Icon                  jsonString  `json:"icon"`
+	Theme                 jsonString  `json:"theme"`
+	Published             jsonBool    `json:"published"`
+	ShowTags              jsonBool    `json:"showTags"`
+	DomainNameList        jsonStrings `json:"domainNameList"`
+	CustomCSS             jsonString  `json:"customCSS"`
+	FooterText            jsonString  `json:"footerText"`
+	ShowPoweredBy         jsonBool    `json:"showPoweredBy"`
+	ShowCertificateExpiry jsonBool    `json:"showCertificateExpiry"`
+	GoogleAnalyticsID     jsonString  `json:"googleAnalyticsId"`
+}
+
+// publicGroupPayload mirrors Group.toPublicJSON() on the Uptime Kuma server
+type publicGroupPayload struct {
+	ID       jsonInt    `json:"id"`
+	Name     jsonString `json:"name"`
+	Monitors []struct {
+		ID      jsonInt  `json:"id"`
+		SendURL jsonBool `json:"sendUrl"`
+	} `json:"monitorList"`
+}
+
+func decodeStatusPage(data []byte) (*StatusPage, error) {
+	var payload statusPagePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("decoding status page: %w", err)
+	}
+
+	return &StatusPage{
+		ID:                    int(payload.ID),
+		Slug:                  string(payload.Slug),
+		Title:                 string(payload.Title),
+		Description:           string(payload.Description),
+		Icon:                  string(payload.Icon),
+		Theme:                 string(payload.Theme),
+		Published:             bool(payload.Published),
+		ShowTags:              bool(payload.ShowTags),
+		DomainNameList:        []string(payload.DomainNameList),
+		CustomCSS:             string(payload.CustomCSS),
+		FooterText:            string(payload.FooterText),
+		ShowPoweredBy:         bool(payload.ShowPoweredBy),
+		ShowCertificateExpiry: bool(payload.ShowCertificateExpiry),
+		GoogleAnalyticsID:     string(payload.GoogleAnalyticsID),
+	}, nil
+}
+
+func decodePublicGroups(data []byte) ([]PublicGroup, error) {
+	var payloads []publicGroupPayload
+	if err := json.Unmarshal(data, &payloads); err != nil {
+		return nil, fmt.Errorf("decoding public groups: %w", err)
+	}
+
+	groups := make([]PublicGroup, len(payloads))
+	for i, payload := range payloads {
+		groups[i] = PublicGroup{ID: int(payload.ID), Name: string(payload.Name)}
+		for _, monitor := range payload.Monitors {
+			groups[i].Monitors = append(groups[i].Monitors, PublicMonitor{ID: int(monitor.ID), SendURL: bool(monitor.SendURL)})
+		}
+	}
+	return groups, nil
+}
+
+// isMissingStatusPage reports whether getStatusPage or saveStatusPage failed
+// because no page has the slug. Uptime Kuma answers "No slug?", or fails
+// dereferencing the missing page on older versions.
+func isMissingStatusPage(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Msg)
+	return strings.Contains(msg, "no slug") || strings.Contains(msg, "of null")
+}
+
+// GetStatusPage retrieves the configuration of a status page
+func (c *Client) GetStatusPage(ctx context.Context, slug string) (*StatusPage, error) {
+	response, err := c.call(ctx, "getStatusPage", slug)
+	if isMissingStatusPage(err) {
+		return nil, fmt.Errorf("status page %q: %w", slug, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+
+	data, err := json.Marshal(response["config"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+	return decodeStatusPage(data)
+}
+
+// CreateStatusPage creates a status page and saves the rest of its
+// configuration and its public groups, since addStatusPage only takes the
+// title and slug
+func (c *Client) CreateStatusPage(ctx context.Context, page *StatusPage, groups []PublicGroup) (*StatusPage, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.call(ctx, "addStatusPage", page.Title, page.Slug); err != nil {
+		return nil, fmt.Errorf("failed to create status page: %w", err)
+	}
+
+	if _, err := c.SaveStatusPage(ctx, page.Slug, page, groups); err != nil {
+		// Do not leave a half-configured page behind
+		if _, deleteErr := c.call(ctx, "deleteStatusPage", page.Slug); deleteErr != nil {
+			return nil, fmt.Errorf("%w (removing the new page failed too: %s)", err, deleteErr)
+		}
+		return nil, err
+	}
+
+	return c.GetStatusPage(ctx, page.Slug)
+}
+
+// SaveStatusPage saves the configuration of the status page with the given
+// slug, renaming it if page.Slug differs, and replaces its public groups:
+// groups without an ID are created and groups left out are deleted. It
+// returns the saved groups with their IDs.
+func (c *Client) SaveStatusPage(ctx context.Context, slug string, page *StatusPage, groups []PublicGroup) ([]PublicGroup, error) {
+	config := map[string]interface{}{
+		"slug":                  page.Slug,
+		"title":                 page.Title,
+		"description":           page.Description,
+		"icon":                  page.Icon,
+		"logo":                  page.Icon,
+		"theme":                 page.Theme,
+		"showTags":              page.ShowTags,
+		"domainNameList":        append([]string{}, page.DomainNameList...),
+		"customCSS":             page.CustomCSS,
+		"footerText":            page.FooterText,
+		"showPoweredBy":         page.ShowPoweredBy,
+		"showCertificateExpiry": page.ShowCertificateExpiry,
+		"googleAnalyticsId":     page.GoogleAnalyticsID,
+	}
+	// Uptime Kuma iterates over every monitorList, so none may be null
+	groupList := make([]PublicGroup, len(groups))
+	for i, group := range groups {
+		groupList[i] = group
+		groupList[i].Monitors = append([]PublicMonitor{}, group.Monitors...)
+	}
+
+	// The icon is passed separately; Uptime Kuma stores PNG data URLs as an
+	// upload and anything else as the icon URL
+	response, err := c.call(ctx, "saveStatusPage", slug, config, page.Icon, groupList)
+	if isMissingStatusPage(err) {
+		return nil, fmt.Errorf("status page %q: %w", slug, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save status page: %w", err)
+	}
+
+	data, err := json.Marshal(response["publicGroupList"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to save status page: %w", err)
+	}
+	return decodePublicGroups(data)
+}
+
+// GetPublicGroups retrieves the public groups of a status page in display
+// order. Uptime Kuma has no socket event for them, so they are read from the
+// HTTP API the status page itself uses.
+func (c *Client) GetPublicGroups(ctx context.Context, slug string) ([]PublicGroup, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/status-page/" + url.PathEscape(slug)
+	u.RawQuery = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public groups: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("status page %q: %w", slug, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get public groups: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public groups: %w", err)
+	}
+	var data struct {
+		PublicGroupList json.RawMessage `json:"publicGroupList"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to get public groups: %w", err)
+	}
+	if len(data.PublicGroupList) == 0 || string(data.PublicGroupList) == "null" {
+		return nil, nil
+	}
+	return decodePublicGroups(data.PublicGroupList)
+}
+
+// DeleteStatusPage deletes a status page with its public groups and incidents
+func (c *Client) DeleteStatusPage(ctx context.Context, slug string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	// deleteStatusPage succeeds for slugs that do not exist, so look the
+	// page up to report pages that are already gone
+	if _, err := c.GetStatusPage(ctx, slug); err != nil {
+		return err
+	}
+
+	if _, err := c.call(ctx, "deleteStatusPage", slug); err != nil {
+		return fmt.Errorf("failed to delete status page: %w", err)
+	}
+
+	return nil
+}
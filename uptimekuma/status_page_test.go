@@ -0,0 +1,113 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestClient_StatusPageLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	created, err := client.CreateStatusPage(ctx, &StatusPage{
+		Slug:          "public",
+		Title:         "Public Status",
+		Description:   "Our services",
+		Theme:         "dark",
+		FooterText:    "Operated by ops",
+		ShowPoweredBy: false,
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateStatusPage: %s", err)
+	}
+	if created.ID == 0 || created.Slug != "public" || created.Title != "Public Status" || created.Description != "Our services" ||
+		created.Theme != "dark" || created.FooterText != "Operated by ops" || created.ShowPoweredBy {
+		t.Fatalf("unexpected status page: %+v", created)
+	}
+	if created.Icon != "/icon.svg" {
+		t.Errorf("expected default icon, got %q", created.Icon)
+	}
+	if _, ok := server.StatusPage("public"); !ok {
+		t.Fatal("status page not stored on server")
+	}
+
+	monitorID := server.AddMonitor(map[string]interface{}{"name": "api", "type": "http", "url": "https://example.com"})
+	groups, err := client.SaveStatusPage(ctx, "public", created, []PublicGroup{
+		{Name: "Services", Monitors: []PublicMonitor{{ID: monitorID, SendURL: true}}},
+		{Name: "Empty"},
+	})
+	if err != nil {
+		t.Fatalf("SaveStatusPage: %s", err)
+	}
+	if len(groups) != 2 || groups[0].ID == 0 || groups[0].Name != "Services" || groups[1].Name != "Empty" {
+		t.Fatalf("unexpected saved groups: %+v", groups)
+	}
+
+	public, err := client.GetPublicGroups(ctx, "public")
+	if err != nil {
+		t.Fatalf("GetPublicGroups: %s", err)
+	}
+	want := []PublicGroup{
+		{ID: groups[0].ID, Name: "Services", Monitors: []PublicMonitor{{ID: monitorID, SendURL: true}}},
+		{ID: groups[1].ID, Name: "Empty"},
+	}
+	if !reflect.DeepEqual(public, want) {
+		t.Errorf("expected public groups %+v, got %+v", want, public)
+	}
+
+	if err := client.DeleteStatusPage(ctx, "public"); err != nil {
+		t.Fatalf("DeleteStatusPage: %s", err)
+	}
+	if _, ok := server.StatusPage("public"); ok {
+		t.Error("expected status page to be deleted on server")
+	}
+	if _, err := client.GetStatusPage(ctx, "public"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got: %v", err)
+	}
+	if _, err := client.GetPublicGroups(ctx, "public"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound reading groups after delete, got: %v", err)
+	}
+	if err := client.DeleteStatusPage(ctx, "public"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting again, got: %v", err)
+	}
+	if _, err := client.SaveStatusPage(ctx, "public", created, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound saving a deleted page, got: %v", err)
+	}
+}
+
+func TestClient_CreateStatusPageRejected(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	server.AddStatusPage("public", "Public")
+
+	tests := []struct {
+		name string
+		page StatusPage
+		want string
+	}{
+		{"invalid slug", StatusPage{Slug: "Not a slug", Title: "Bad"}, "Invalid Slug"},
+		{"duplicate slug", StatusPage{Slug: "public", Title: "Again"}, "UNIQUE constraint"},
+		{"non-PNG icon", StatusPage{Slug: "logo", Title: "Logo", Icon: "data:image/jpeg;base64,AAAA"}, "Only allowed PNG logo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateStatusPage(ctx, &tt.page, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+			if tt.page.Slug != "public" {
+				if _, ok := server.StatusPage(tt.page.Slug); ok {
+					t.Errorf("expected status page %q not to be left behind", tt.page.Slug)
+				}
+			}
+		})
+	}
+}
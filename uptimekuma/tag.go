@@ -0,0 +1,286 @@
+package uptimekuma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Tag is a tag definition that monitors can be labelled with
+type Tag struct {
+	ID    int    `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// MonitorTag is a tag assigned to a monitor. The same tag may be assigned
+// more than once with different values.
+type MonitorTag struct {
+	TagID int    `json:"tag_id"`
+	Name  string `json:"name,omitempty"`  // Read only
+	Color string `json:"color,omitempty"` // Read only
+	Value string `json:"value"`
+}
+
+// tagPayload mirrors Tag.toJSON() on the Uptime Kuma server
+type tagPayload struct {
+	ID    jsonInt    `json:"id"`
+	Name  jsonString `json:"name"`
+	Color jsonString `json:"color"`
+}
+
+// decodeTag converts a tag from an acknowledgement
+func decodeTag(v interface{}) (Tag, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	var payload tagPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return Tag{}, fmt.Errorf("decoding tag: %w", err)
+	}
+	return Tag{ID: int(payload.ID), Name: string(payload.Name), Color: string(payload.Color)}, nil
+}
+
+// GetTags retrieves all tag definitions. Unlike monitors and notifications,
+// Uptime Kuma does not push tags, so every call asks the server.
+func (c *Client) GetTags(ctx context.Context) ([]Tag, error) {
+	response, err := c.call(ctx, "getTags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	items, _ := response["tags"].([]interface{})
+	tags := make([]Tag, 0, len(items))
+	for _, item := range items {
+		tag, err := decodeTag(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+
+	return tags, nil
+}
+
+// GetTag retrieves a specific tag definition by ID
+func (c *Client) GetTag(ctx context.Context, id int) (*Tag, error) {
+	tags, err := c.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		if tag.ID == id {
+			return &tag, nil
+		}
+	}
+
+	return nil, fmt.Errorf("tag with ID %d: %w", id, ErrNotFound)
+}
+
+// CreateTag creates a tag definition
+func (c *Client) CreateTag(ctx context.Context, tag *Tag) (*Tag, error) {
+	response, err := c.call(ctx, "addTag", map[string]interface{}{
+		"name":  tag.Name,
+		"color": tag.Color,
+		"new":   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	created, err := decodeTag(response["tag"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	if created.ID == 0 {
+		return nil, fmt.Errorf("failed to create tag: no ID in response")
+	}
+
+	return &created, nil
+}
+
+// UpdateTag renames or recolours a tag definition
+func (c *Client) UpdateTag(ctx context.Context, tag *Tag) (*Tag, error) {
+	response, err := c.call(ctx, "editTag", map[string]interface{}{
+		"id":    tag.ID,
+		"name":  tag.Name,
+		"color": tag.Color,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	updated, err := decodeTag(response["tag"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+	if updated.ID == 0 {
+		// Without the saved tag in the acknowledgement, assume the values
+		// sent were stored
+		updated = *tag
+	}
+
+	return &updated, nil
+}
+
+// DeleteTag deletes a tag definition and removes it from every monitor
+func (c *Client) DeleteTag(ctx context.Context, id int) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	// deleteTag succeeds for IDs that do not exist, so look the tag up to
+	// report tags that are already gone
+	if _, err := c.GetTag(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := c.call(ctx, "deleteTag", id); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+// AddMonitorTag assigns a tag with a value to a monitor. Uptime Kuma does not
+// push the monitor list afterwards; call RefreshMonitors to see the change.
+func (c *Client) AddMonitorTag(ctx context.Context, tagID, monitorID int, value string) error {
+	if _, err := c.call(ctx, "addMonitorTag", tagID, monitorID, value); err != nil {
+		return fmt.Errorf("failed to add tag %d to monitor %d: %w", tagID, monitorID, err)
+	}
+	return nil
+}
+
+// UpdateMonitorTag changes the value of every assignment of a tag to a monitor
+func (c *Client) UpdateMonitorTag(ctx context.Context, tagID, monitorID int, value string) error {
+	if _, err := c.call(ctx, "editMonitorTag", tagID, monitorID, value); err != nil {
+		return fmt.Errorf("failed to update tag %d on monitor %d: %w", tagID, monitorID, err)
+	}
+	return nil
+}
+
+// DeleteMonitorTag removes the assignment of a tag with the given value from
+// a monitor
+func (c *Client) DeleteMonitorTag(ctx context.Context, tagID, monitorID int, value string) error {
+	if _, err := c.call(ctx, "deleteMonitorTag", tagID, monitorID, value); err != nil {
+		return fmt.Errorf("failed to remove tag %d from monitor %d: %w", tagID, monitorID, err)
+	}
+	return nil
+}
+
+// SetMonitorTags makes the tags of a monitor match tags, changing only the
+// assignments that differ, and refreshes the monitor list
+func (c *Client) SetMonitorTags(ctx context.Context, monitorID int, tags []MonitorTag) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if err := c.RefreshMonitors(ctx); err != nil {
+		return err
+	}
+	monitor, err := c.GetMonitor(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+
+	remove, add := diffMonitorTags(monitor.Tags, tags)
+
+	// A tag assigned once before and after only needs its value changed.
+	// editMonitorTag updates every assignment of the tag, so it is only
+	// used when there is a single one.
+	current := countMonitorTags(monitor.Tags)
+	removed, added := countMonitorTags(remove), countMonitorTags(add)
+	var edit []MonitorTag
+	for tagID, n := range added {
+		if n == 1 && removed[tagID] == 1 && current[tagID] == 1 {
+			edit = append(edit, takeMonitorTag(&add, tagID))
+			takeMonitorTag(&remove, tagID)
+		}
+	}
+	sort.Slice(edit, func(i, j int) bool { return edit[i].TagID < edit[j].TagID })
+
+	tflog.Debug(ctx, "Reconciling monitor tags", map[string]interface{}{
+		"monitor_id": monitorID,
+		"remove":     len(remove),
+		"edit":       len(edit),
+		"add":        len(add),
+	})
+
+	for _, tag := range remove {
+		if err := c.DeleteMonitorTag(ctx, tag.TagID, monitorID, tag.Value); err != nil {
+			return err
+		}
+	}
+	for _, tag := range edit {
+		if err := c.UpdateMonitorTag(ctx, tag.TagID, monitorID, tag.Value); err != nil {
+			return err
+		}
+	}
+	for _, tag := range add {
+		if err := c.AddMonitorTag(ctx, tag.TagID, monitorID, tag.Value); err != nil {
+			return err
+		}
+	}
+
+	if len(remove)+len(edit)+len(add) == 0 {
+		return nil
+	}
+	return c.RefreshMonitors(ctx)
+}
+
+// monitorTagKey identifies an assignment; name and colour belong to the tag
+type monitorTagKey struct {
+	tagID int
+	value string
+}
+
+// diffMonitorTags returns the assignments of current missing from desired
+// and those of desired missing from current, in their original order
+func diffMonitorTags(current, desired []MonitorTag) (remove, add []MonitorTag) {
+	want := make(map[monitorTagKey]bool, len(desired))
+	for _, tag := range desired {
+		want[monitorTagKey{tag.TagID, tag.Value}] = true
+	}
+	have := make(map[monitorTagKey]bool, len(current))
+	for _, tag := range current {
+		key := monitorTagKey{tag.TagID, tag.Value}
+		if !want[key] && !have[key] {
+			remove = append(remove, tag)
+		}
+		have[key] = true
+	}
+	for _, tag := range desired {
+		key := monitorTagKey{tag.TagID, tag.Value}
+		if !have[key] {
+			add = append(add, tag)
+			have[key] = true
+		}
+	}
+	return remove, add
+}
+
+// countMonitorTags counts the assignments of each tag
+func countMonitorTags(tags []MonitorTag) map[int]int {
+	counts := make(map[int]int, len(tags))
+	for _, tag := range tags {
+		counts[tag.TagID]++
+	}
+	return counts
+}
+
+// takeMonitorTag removes the first assignment of a tag from tags and returns it
+func takeMonitorTag(tags *[]MonitorTag, tagID int) MonitorTag {
+	for i, tag := range *tags {
+		if tag.TagID == tagID {
+			*tags = append((*tags)[:i], (*tags)[i+1:]...)
+			return tag
+		}
+	}
+	return MonitorTag{}
+}
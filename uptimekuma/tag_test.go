@@ -0,0 +1,142 @@
+package uptimekuma
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/j0r15/terraform-provider-uptimekuma/internal/fakekuma"
+)
+
+func TestClient_TagLifecycle(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	created, err := client.CreateTag(ctx, &Tag{Name: "env:prod", Color: "#DC2626"})
+	if err != nil {
+		t.Fatalf("CreateTag: %s", err)
+	}
+	if created.ID == 0 || created.Name != "env:prod" || created.Color != "#DC2626" {
+		t.Fatalf("unexpected tag: %+v", created)
+	}
+	if _, ok := server.Tag(created.ID); !ok {
+		t.Fatalf("tag %d not stored on server", created.ID)
+	}
+
+	created.Color = "#2563EB"
+	updated, err := client.UpdateTag(ctx, created)
+	if err != nil {
+		t.Fatalf("UpdateTag: %s", err)
+	}
+	if updated.Color != "#2563EB" {
+		t.Errorf("expected updated colour, got %+v", updated)
+	}
+
+	other := server.AddTag("team:web", "#059669")
+	tags, err := client.GetTags(ctx)
+	if err != nil {
+		t.Fatalf("GetTags: %s", err)
+	}
+	if len(tags) != 2 || tags[0].ID != created.ID || tags[1].ID != other || tags[1].Name != "team:web" {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+
+	if err := client.DeleteTag(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteTag: %s", err)
+	}
+	if _, ok := server.Tag(created.ID); ok {
+		t.Error("expected tag to be deleted on server")
+	}
+	if _, err := client.GetTag(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got: %v", err)
+	}
+	if err := client.DeleteTag(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting again, got: %v", err)
+	}
+	if _, err := client.UpdateTag(ctx, created); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound updating a deleted tag, got: %v", err)
+	}
+}
+
+func TestClient_SetMonitorTags(t *testing.T) {
+	ctx := context.Background()
+	server := fakekuma.NewServer(t)
+	client := newTestClient(t, server)
+
+	team := server.AddTag("team", "#2563EB")
+	prod := server.AddTag("env:prod", "#DC2626")
+	monitorID := server.AddMonitor(map[string]interface{}{"name": "api", "type": "http", "url": "https://example.com"})
+
+	monitorTags := func() []MonitorTag {
+		t.Helper()
+		if err := client.RefreshMonitors(ctx); err != nil {
+			t.Fatalf("RefreshMonitors: %s", err)
+		}
+		monitor, err := client.GetMonitor(ctx, monitorID)
+		if err != nil {
+			t.Fatalf("GetMonitor: %s", err)
+		}
+		return monitor.Tags
+	}
+
+	// Assign
+	if err := client.SetMonitorTags(ctx, monitorID, []MonitorTag{{TagID: team, Value: "payments"}, {TagID: prod}}); err != nil {
+		t.Fatalf("SetMonitorTags: %s", err)
+	}
+	want := []MonitorTag{
+		{TagID: team, Name: "team", Color: "#2563EB", Value: "payments"},
+		{TagID: prod, Name: "env:prod", Color: "#DC2626"},
+	}
+	if got := monitorTags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	// Changing a value edits the assignment in place; dropping a tag removes it
+	before := len(server.Events())
+	if err := client.SetMonitorTags(ctx, monitorID, []MonitorTag{{TagID: team, Value: "billing"}}); err != nil {
+		t.Fatalf("SetMonitorTags: %s", err)
+	}
+	events := server.Events()[before:]
+	if countEvents(events, "editMonitorTag") != 1 || countEvents(events, "deleteMonitorTag") != 1 || countEvents(events, "addMonitorTag") != 0 {
+		t.Errorf("expected one edit and one delete, got events %v", events)
+	}
+	want = []MonitorTag{{TagID: team, Name: "team", Color: "#2563EB", Value: "billing"}}
+	if got := monitorTags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	// A second value for the same tag is added next to the first
+	if err := client.SetMonitorTags(ctx, monitorID, []MonitorTag{{TagID: team, Value: "billing"}, {TagID: team, Value: "payments"}}); err != nil {
+		t.Fatalf("SetMonitorTags: %s", err)
+	}
+	if got := monitorTags(); len(got) != 2 {
+		t.Errorf("expected two assignments of the team tag, got %+v", got)
+	}
+
+	// Nothing is sent when the tags already match
+	before = len(server.Events())
+	if err := client.SetMonitorTags(ctx, monitorID, []MonitorTag{{TagID: team, Value: "payments"}, {TagID: team, Value: "billing"}}); err != nil {
+		t.Fatalf("SetMonitorTags: %s", err)
+	}
+	for _, event := range server.Events()[before:] {
+		if strings.HasSuffix(event, "MonitorTag") {
+			t.Errorf("unexpected %s when tags already match", event)
+		}
+	}
+
+	// Deleting the tag removes its assignments
+	if err := client.DeleteTag(ctx, team); err != nil {
+		t.Fatalf("DeleteTag: %s", err)
+	}
+	if got := monitorTags(); len(got) != 0 {
+		t.Errorf("expected no tags after deleting the tag, got %+v", got)
+	}
+
+	// Assigning a tag that does not exist fails
+	if err := client.SetMonitorTags(ctx, monitorID, []MonitorTag{{TagID: 99}}); err == nil {
+		t.Error("expected error assigning a missing tag")
+	}
+}
@@ -0,0 +1,82 @@
+package uptimekuma
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// tokenCache keeps session tokens on disk between runs, keyed by
+// instance URL and username, so the password is only sent when a token expires
+type tokenCache struct {
+	path string
+}
+
+// tokenCacheKey identifies the session of a user on an instance
+func tokenCacheKey(baseURL, username string) string {
+	return baseURL + " " + username
+}
+
+// load returns the cached token for key, or "" if there is none
+func (tc tokenCache) load(key string) string {
+	tokens, err := tc.read()
+	if err != nil {
+		return ""
+	}
+	return tokens[key]
+}
+
+// store saves the token for key, keeping the tokens of other instances
+func (tc tokenCache) store(key, token string) error {
+	tokens, err := tc.read()
+	if err != nil {
+		tokens = map[string]string{}
+	}
+	tokens[key] = token
+
+	content, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(tc.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating token cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so parallel runs never read a partial file
+	tmp, err := os.CreateTemp(dir, ".uptimekuma-token-*")
+	if err != nil {
+		return fmt.Errorf("writing token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing token cache: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), tc.path)
+}
+
+func (tc tokenCache) read() (map[string]string, error) {
+	content, err := os.ReadFile(tc.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(content, &tokens); err != nil {
+		return nil, fmt.Errorf("parsing token cache %s: %w", tc.path, err)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,88 @@
+package uptimekuma
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Transport opens the connections a Client runs its Socket.IO sessions over.
+// The default dials a WebSocket; Recorder and Replayer wrap or replace it to
+// capture and replay sessions in tests.
+type Transport interface {
+	// Dial connects to the Socket.IO endpoint at url, sending header with
+	// the upgrade request
+	Dial(ctx context.Context, url string, header http.Header) (Conn, error)
+}
+
+// Conn is a connection opened by a Transport, carrying Engine.IO packets as
+// text frames
+type Conn interface {
+	// ReadFrame blocks until the next frame arrives or the read deadline passes
+	ReadFrame() (string, error)
+	// WriteFrame sends a frame; it is never called concurrently
+	WriteFrame(frame string) error
+	// SetReadDeadline bounds ReadFrame; the zero time removes the bound
+	SetReadDeadline(t time.Time) error
+	// SetWriteDeadline bounds WriteFrame; the zero time removes the bound
+	SetWriteDeadline(t time.Time) error
+	// Close ends the connection and unblocks a pending ReadFrame
+	Close() error
+}
+
+// WithTransport replaces the WebSocket transport, e.g. with a Replayer in tests
+func WithTransport(transport Transport) ClientOption {
+	return func(c *Client) {
+		c.Transport = transport
+	}
+}
+
+// transport returns the configured Transport, or a WebSocket transport with
+// the client's TLS and proxy settings
+func (c *Client) transport() Transport {
+	if c.Transport != nil {
+		return c.Transport
+	}
+	return &websocketTransport{dialer: c.dialer()}
+}
+
+// websocketTransport dials WebSocket connections
+type websocketTransport struct {
+	dialer *websocket.Dialer
+}
+
+func (t *websocketTransport) Dial(ctx context.Context, url string, header http.Header) (Conn, error) {
+	ws, _, err := t.dialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	return &websocketConn{ws: ws}, nil
+}
+
+// websocketConn is a Conn over a WebSocket
+type websocketConn struct {
+	ws *websocket.Conn
+}
+
+func (c *websocketConn) ReadFrame() (string, error) {
+	_, message, err := c.ws.ReadMessage()
+	return string(message), err
+}
+
+func (c *websocketConn) WriteFrame(frame string) error {
+	return c.ws.WriteMessage(websocket.TextMessage, []byte(frame))
+}
+
+func (c *websocketConn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+func (c *websocketConn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
+
+func (c *websocketConn) Close() error {
+	return c.ws.Close()
+}